@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"exchanges/pkg/config"
+	"exchanges/pkg/exchange"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+type cliFlags struct {
+	config   string
+	json     bool
+	watch    bool
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func newFlagSet(name string, opts *cliFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&opts.config, "config", "", "Path to JSON config file with exchange settings")
+	fs.BoolVar(&opts.json, "json", false, "Print JSON instead of a table")
+
+	return fs
+}
+
+// newFetchFlagSet adds the flags of commands that call an exchange and can refresh their output.
+func newFetchFlagSet(name string, opts *cliFlags) *flag.FlagSet {
+	fs := newFlagSet(name, opts)
+	fs.BoolVar(&opts.watch, "watch", false, "Refresh the output until interrupted")
+	fs.DurationVar(&opts.interval, "interval", time.Second*2, "Refresh interval in watch mode")
+	fs.DurationVar(&opts.timeout, "timeout", time.Second*15, "Request timeout")
+
+	return fs
+}
+
+// parseArgs lets flags follow positional arguments, e.g. `pairs bybit -quote USDT`.
+func parseArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != want {
+		return nil, fmt.Errorf("%s: expected %d arguments, got %d", fs.Name(), want, len(positional))
+	}
+
+	return positional, nil
+}
+
+func listExchanges(args []string) error {
+	var opts cliFlags
+
+	fs := newFlagSet("exchanges", &opts)
+
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	cfg, err := cliConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	return output(opts, cfg.EnabledExchanges(), func(w io.Writer) {
+		for _, row := range cfg.EnabledExchanges() {
+			_, _ = fmt.Fprintln(w, row)
+		}
+	})
+}
+
+func listPairs(args []string) error {
+	var (
+		opts   cliFlags
+		filter exchange.PairFilter
+		limit  int
+	)
+
+	fs := newFetchFlagSet("pairs", &opts)
+	fs.StringVar(&filter.Base, "base", "", "Base asset filter")
+	fs.StringVar(&filter.Quote, "quote", "", "Quote asset filter")
+	fs.StringVar(&filter.Prefix, "prefix", "", "Pair id prefix")
+	fs.StringVar(&filter.Search, "q", "", "Search term matched against id, base and quote")
+	fs.StringVar(&filter.SortBy, "sort", exchange.SortByID, "Sort key: "+strings.Join(exchange.SortKeys, ", "))
+	fs.BoolVar(&filter.Desc, "desc", false, "Sort in descending order")
+	fs.IntVar(&limit, "limit", 0, "Maximum number of pairs, all when 0")
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if !exchange.ValidSortKey(filter.SortBy) {
+		return fmt.Errorf("sort must be one of %s", strings.Join(exchange.SortKeys, ", "))
+	}
+
+	obj, err := cliExchange(opts, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return watch(opts, func(ctx context.Context) error {
+		pairs, err := obj.GetPairs(ctx)
+		if err != nil {
+			return err
+		}
+
+		pairs = filter.Apply(pairs)
+
+		if limit > 0 && len(pairs) > limit {
+			pairs = pairs[:limit]
+		}
+
+		return output(opts, pairs, func(w io.Writer) {
+			_, _ = fmt.Fprintln(w, "ID\tBASE\tQUOTE\tBID\tASK\tSPREAD %\tVOLUME\t")
+
+			for _, row := range pairs {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+					row.Id, row.BaseAsset, row.QuoteAsset, row.Bid, row.Ask, row.Spread().Shift(2).StringFixed(4), row.Volume)
+			}
+		})
+	})
+}
+
+func showBook(args []string) error {
+	var (
+		opts  cliFlags
+		depth int
+	)
+
+	fs := newFetchFlagSet("book", &opts)
+	fs.IntVar(&depth, "depth", 10, "Levels per side, full book when 0")
+
+	positional, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+
+	obj, err := cliExchange(opts, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return watch(opts, func(ctx context.Context) error {
+		book, err := obj.GetOrderBook(ctx, positional[1])
+		if err != nil {
+			return err
+		}
+
+		book.Sort()
+		book = book.Truncate(depth)
+
+		return output(opts, book, func(w io.Writer) {
+			_, _ = fmt.Fprintln(w, "BID AMOUNT\tBID\tASK\tASK AMOUNT\t")
+
+			for i := 0; i < len(book.Bid) || i < len(book.Ask); i++ {
+				row := make([]string, 4)
+
+				if i < len(book.Bid) {
+					row[0], row[1] = book.Bid[i][1].String(), book.Bid[i][0].String()
+				}
+
+				if i < len(book.Ask) {
+					row[2], row[3] = book.Ask[i][0].String(), book.Ask[i][1].String()
+				}
+
+				_, _ = fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+			}
+		})
+	})
+}
+
+func cliConfig(opts cliFlags) (*config.Config, error) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	return config.Load(opts.config)
+}
+
+func cliExchange(opts cliFlags, exchangeID string) (exchange.Exchange, error) {
+	cfg, err := cliConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := cfg.Exchanges[exchangeID]
+
+	if !ok && !exchange.Registered(exchangeID) {
+		return nil, fmt.Errorf("unknown exchange %q, known: %s", exchangeID, strings.Join(exchange.Names(), ", "))
+	}
+
+	obj, err := value.New(exchangeID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", exchangeID, err)
+	}
+
+	return obj, nil
+}
+
+func watch(opts cliFlags, fn func(ctx context.Context) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	run := func() error {
+		ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+
+		return fn(ctx)
+	}
+
+	if !opts.watch {
+		return run()
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		if !opts.json {
+			fmt.Print("\033[H\033[2J")
+			fmt.Println(time.Now().Format(time.RFC3339))
+		}
+
+		if err := run(); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func output(opts cliFlags, value any, table func(w io.Writer)) error {
+	if opts.json {
+		return json.NewEncoder(os.Stdout).Encode(value)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	table(w)
+
+	return w.Flush()
+}
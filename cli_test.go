@@ -0,0 +1,114 @@
+package main
+
+import (
+	"exchanges/pkg/exchange"
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testFlagSet(fs *flag.FlagSet) *flag.FlagSet {
+	fs.Init(fs.Name(), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	return fs
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		positional []string
+		opts       cliFlags
+		filter     exchange.PairFilter
+		wantErr    bool
+	}{
+		{
+			name:       "flags_after",
+			args:       []string{"bybit", "-quote", "USDT", "-desc", "-json"},
+			want:       1,
+			positional: []string{"bybit"},
+			opts:       cliFlags{json: true, interval: time.Second * 2, timeout: time.Second * 15},
+			filter:     exchange.PairFilter{Quote: "USDT", SortBy: exchange.SortByID, Desc: true},
+		},
+		{
+			name:       "flags_around",
+			args:       []string{"-sort", "volume", "okx", "-watch", "-interval", "5s"},
+			want:       1,
+			positional: []string{"okx"},
+			opts:       cliFlags{watch: true, interval: time.Second * 5, timeout: time.Second * 15},
+			filter:     exchange.PairFilter{SortBy: exchange.SortByVolume},
+		},
+		{
+			name:       "two_positional",
+			args:       []string{"okx", "-base=BTC", "extra"},
+			want:       2,
+			positional: []string{"okx", "extra"},
+			opts:       cliFlags{interval: time.Second * 2, timeout: time.Second * 15},
+			filter:     exchange.PairFilter{Base: "BTC", SortBy: exchange.SortByID},
+		},
+		{
+			name:       "terminator",
+			args:       []string{"okx", "--", "-base"},
+			want:       2,
+			positional: []string{"okx", "-base"},
+			opts:       cliFlags{interval: time.Second * 2, timeout: time.Second * 15},
+			filter:     exchange.PairFilter{SortBy: exchange.SortByID},
+		},
+		{name: "missing", args: []string{"-json"}, want: 1, wantErr: true},
+		{name: "too_many", args: []string{"okx", "bybit"}, want: 1, wantErr: true},
+		{name: "unknown_flag", args: []string{"okx", "-nope"}, want: 1, wantErr: true},
+		{name: "bad_value", args: []string{"okx", "-interval", "soon"}, want: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				opts   cliFlags
+				filter exchange.PairFilter
+			)
+
+			fs := testFlagSet(newFetchFlagSet("pairs", &opts))
+			fs.StringVar(&filter.Base, "base", "", "")
+			fs.StringVar(&filter.Quote, "quote", "", "")
+			fs.StringVar(&filter.SortBy, "sort", exchange.SortByID, "")
+			fs.BoolVar(&filter.Desc, "desc", false, "")
+
+			positional, err := parseArgs(fs, tt.args, tt.want)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(positional, tt.positional) {
+				t.Errorf("positional = %q, want %q", positional, tt.positional)
+			}
+
+			if opts != tt.opts || filter != tt.filter {
+				t.Errorf("opts = %+v %+v, want %+v %+v", opts, filter, tt.opts, tt.filter)
+			}
+		})
+	}
+}
+
+func TestExchangesRejectsWatch(t *testing.T) {
+	var opts cliFlags
+
+	for _, args := range [][]string{{"-watch"}, {"-interval", "1s"}, {"-timeout", "1s"}} {
+		if _, err := parseArgs(testFlagSet(newFlagSet("exchanges", &opts)), args, 0); err == nil {
+			t.Errorf("exchanges %v: want error", args)
+		}
+	}
+
+	if _, err := parseArgs(testFlagSet(newFlagSet("exchanges", &opts)), []string{"-json"}, 0); err != nil || !opts.json {
+		t.Errorf("exchanges -json: %v", err)
+	}
+}
@@ -1,57 +1,65 @@
 package main
 
 import (
-	"context"
-	"exchanges/pkg/exchange/bybit"
-	"exchanges/pkg/exchange/gateio"
-	"exchanges/pkg/exchange/okx"
-	"exchanges/pkg/server"
-	"flag"
-	"log"
+	_ "exchanges/pkg/exchange/bybit"
+	_ "exchanges/pkg/exchange/gateio"
+	_ "exchanges/pkg/exchange/generic"
+	_ "exchanges/pkg/exchange/okx"
+	_ "exchanges/pkg/exchange/sim"
+	"fmt"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 )
 
-var (
-	logFile string
-	addr    string
-)
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
 
-func init() {
-	flag.StringVar(&logFile, "logFile", "", "Path to log file")
-	flag.StringVar(&addr, "addr", ":8080", "server addres")
-	flag.Parse()
+var commands = []command{
+	{name: "serve", usage: "serve [-config file] [-addr addr] [-logFile file]", run: serve},
+	{name: "exchanges", usage: "exchanges [-config file] [-json]", run: listExchanges},
+	{name: "pairs", usage: "pairs <exchange> [-base asset] [-quote asset] [-prefix id] [-q term] [-sort key] [-desc] [-limit n] [-json] [-watch] [-interval d]", run: listPairs},
+	{name: "book", usage: "book <exchange> <pair> [-depth n] [-json] [-watch] [-interval d]", run: showBook},
 }
 
 func main() {
-	if len(logFile) > 0 {
-		logFile, err := os.Open(logFile)
-		if err != nil {
-			log.Fatal(err)
-		}
+	name, args := "serve", os.Args[1:]
 
-		defer func() {
-			_ = logFile.Close()
-		}()
-
-		log.SetOutput(logFile)
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill, syscall.SIGTERM)
-	defer stop()
+	for _, row := range commands {
+		if row.name == name {
+			if err := row.run(args); err != nil {
+				fatal(err)
+			}
 
-	srv := server.NewServer()
+			return
+		}
+	}
 
-	srv.SetExchange(gateio.NewAPI())
-	srv.SetExchange(bybit.NewAPI())
-	srv.SetExchange(okx.NewAPI())
+	usage()
 
-	log.Print("Application start")
+	if name != "help" {
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [arguments]\n\ncommands:\n", os.Args[0])
 
-	if err := srv.Run(ctx, addr); err != nil {
-		log.Fatalf("Application stop: %v", err)
+	for _, row := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", row.usage)
 	}
 
-	log.Print("Application stop")
+	fmt.Fprintf(os.Stderr, "\nwithout a command the server is started, run '%s <command> -help' for flags\n", os.Args[0])
+}
+
+func fatal(err error) {
+	slog.Error(err.Error())
+	os.Exit(1)
 }
@@ -0,0 +1,5 @@
+package apikey
+
+const (
+	hashPrefix = "sha256:"
+)
@@ -0,0 +1,69 @@
+package apikey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+type Key struct {
+	Name      string
+	Hash      string
+	RateLimit float64
+	RateBurst int
+	Routes    []string
+	Exchanges []string
+	Admin     bool
+	// MaxStreams caps open gRPC streams; zero uses the server default.
+	MaxStreams int
+}
+
+func (k Key) RouteAllowed(path string) bool {
+	if len(k.Routes) == 0 {
+		return true
+	}
+
+	for _, prefix := range k.Routes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k Key) ExchangeAllowed(exchangeID string) bool {
+	if len(k.Exchanges) == 0 {
+		return true
+	}
+
+	for _, row := range k.Exchanges {
+		if row == exchangeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ParseHash(value string) (string, error) {
+	hash, ok := strings.CutPrefix(value, hashPrefix)
+	if !ok {
+		return "", fmt.Errorf("key hash must start with %q", hashPrefix)
+	}
+
+	hash = strings.ToLower(hash)
+
+	if data, err := hex.DecodeString(hash); err != nil || len(data) != sha256.Size {
+		return "", fmt.Errorf("key hash must be %d hex encoded bytes", sha256.Size)
+	}
+
+	return hash, nil
+}
+
+func Sum(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
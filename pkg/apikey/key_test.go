@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHash(t *testing.T) {
+	sum := Sum("secret")
+
+	tests := []struct {
+		value string
+		want  string
+		err   bool
+	}{
+		{value: "sha256:" + sum, want: sum},
+		{value: "sha256:" + strings.ToUpper(sum), want: sum},
+		{value: sum, err: true},
+		{value: "sha256:abc", err: true},
+		{value: "sha256:" + strings.Repeat("z", 64), err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseHash(tt.value)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("ParseHash(%q) = %q, %v", tt.value, got, err)
+		}
+	}
+}
+
+func TestKeyScope(t *testing.T) {
+	key := Key{Routes: []string{"/api/v1/history/"}, Exchanges: []string{"okx"}}
+
+	if !key.RouteAllowed("/api/v1/history/okx/pairs") || key.RouteAllowed("/api/v1/okx/pairs") {
+		t.Error("route prefixes are not applied")
+	}
+
+	if !key.ExchangeAllowed("okx") || key.ExchangeAllowed("bybit") {
+		t.Error("exchange list is not applied")
+	}
+
+	if open := (Key{}); !open.RouteAllowed("/anything") || !open.ExchangeAllowed("bybit") {
+		t.Error("a key without scopes is restricted")
+	}
+}
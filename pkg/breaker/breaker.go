@@ -0,0 +1,159 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+type Config struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+	HalfOpenRequests int
+}
+
+func NewBreaker(cfg Config) *Breaker {
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = defaultFailureThreshold
+	}
+
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = defaultCoolDown
+	}
+
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = defaultHalfOpenRequests
+	}
+
+	return &Breaker{
+		mu:    new(sync.Mutex),
+		cfg:   cfg,
+		state: StateClosed,
+	}
+}
+
+type Breaker struct {
+	mu       *sync.Mutex
+	cfg      Config
+	state    string
+	failures int
+	inFlight int
+	openedAt time.Time
+}
+
+func (b *Breaker) Allow() error {
+	if b.cfg.FailureThreshold < 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if time.Since(b.openedAt) < b.cfg.CoolDown {
+			return ErrOpen
+		}
+
+		b.state = StateHalfOpen
+		b.inFlight = 0
+	}
+
+	if b.state == StateHalfOpen {
+		if b.inFlight >= b.cfg.HalfOpenRequests {
+			return ErrOpen
+		}
+
+		b.inFlight++
+	}
+
+	return nil
+}
+
+func (b *Breaker) Done(success bool) {
+	if b.cfg.FailureThreshold < 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case success:
+		b.state = StateClosed
+		b.failures = 0
+		b.inFlight = 0
+	case b.state == StateHalfOpen:
+		b.open()
+	default:
+		b.failures++
+
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.CoolDown {
+		return StateHalfOpen
+	}
+
+	return b.state
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.inFlight = 0
+}
+
+func NewSet(cfg Config) *Set {
+	return &Set{
+		mu:       new(sync.Mutex),
+		cfg:      cfg,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+type Set struct {
+	mu       *sync.Mutex
+	cfg      Config
+	breakers map[string]*Breaker
+}
+
+func (s *Set) Get(name string) *Breaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.breakers[name]
+	if !ok {
+		obj = NewBreaker(s.cfg)
+		s.breakers[name] = obj
+	}
+
+	return obj
+}
+
+func (s *Set) States() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]string, len(s.breakers))
+
+	for name, obj := range s.breakers {
+		result[name] = obj.State()
+	}
+
+	return result
+}
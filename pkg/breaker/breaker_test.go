@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	b := NewBreaker(Config{FailureThreshold: 2, CoolDown: time.Minute, HalfOpenRequests: 1})
+
+	check := func(state string) {
+		t.Helper()
+
+		if got := b.State(); got != state {
+			t.Fatalf("state = %s, want %s", got, state)
+		}
+	}
+
+	check(StateClosed)
+
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatal(err)
+		}
+
+		b.Done(false)
+	}
+
+	check(StateOpen)
+
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("open breaker allowed a request: %v", err)
+	}
+
+	b.openedAt = b.openedAt.Add(-time.Minute)
+	check(StateHalfOpen)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("half-open breaker rejected the probe: %v", err)
+	}
+
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatal("half-open breaker allowed more probes than configured")
+	}
+
+	b.Done(false)
+	check(StateOpen)
+
+	b.openedAt = b.openedAt.Add(-time.Minute)
+
+	if err := b.Allow(); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Cancel()
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("cancelled probe kept its slot: %v", err)
+	}
+
+	b.Done(true)
+	check(StateClosed)
+
+	b.Done(false)
+	check(StateClosed)
+	b.Done(true)
+	b.Done(false)
+	check(StateClosed)
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := NewBreaker(Config{FailureThreshold: -1})
+
+	for i := 0; i < 100; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatal(err)
+		}
+
+		b.Done(false)
+	}
+
+	if b.State() != StateClosed {
+		t.Errorf("state = %s, want closed", b.State())
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet(Config{FailureThreshold: 1})
+
+	if s.Get("a") != s.Get("a") {
+		t.Fatal("set returned different breakers for one name")
+	}
+
+	s.Get("a").Done(false)
+	s.Get("b").Done(true)
+
+	states := s.States()
+
+	if states["a"] != StateOpen || states["b"] != StateClosed {
+		t.Errorf("states = %v", states)
+	}
+}
@@ -0,0 +1,20 @@
+package breaker
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+
+	defaultFailureThreshold = 5
+	defaultCoolDown         = time.Second * 30
+	defaultHalfOpenRequests = 1
+)
+
+var (
+	ErrOpen = errors.New("circuit open")
+)
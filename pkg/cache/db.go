@@ -1,53 +1,196 @@
 package cache
 
 import (
+	"container/list"
+	"context"
+	"exchanges/pkg/metrics"
 	"sync"
 	"time"
 )
 
-func NewDB() *DB {
+func NewDB(name string) *DB {
+	return NewLimitedDB(name, 0)
+}
+
+func NewLimitedDB(name string, limit int) *DB {
 	return &DB{
-		mu: new(sync.Mutex),
-		data: make(map[string]struct {
-			uTime time.Time
-			data  any
-		}),
+		mu:    new(sync.Mutex),
+		name:  name,
+		limit: limit,
+		data:  make(map[string]*item),
+		order: list.New(),
 	}
 }
 
 type DB struct {
-	mu   *sync.Mutex
-	data map[string]struct {
-		uTime time.Time
-		data  any
-	}
+	mu    *sync.Mutex
+	name  string
+	limit int
+	data  map[string]*item
+	order *list.List
+}
+
+type item struct {
+	elem    *list.Element
+	uTime   time.Time
+	sTime   time.Time
+	data    any
+	err     error
+	refresh bool
 }
 
+type Loader func(ctx context.Context) (any, error)
+
 func (d *DB) Set(key string, timeout time.Duration, data any) {
+	d.SetStale(key, timeout, 0, data)
+}
+
+func (d *DB) SetStale(key string, timeout, staleTimeout time.Duration, data any) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.data[key] = struct {
-		uTime time.Time
-		data  any
-	}{
-		uTime: time.Now().Add(timeout),
+	d.set(key, timeout, staleTimeout, data)
+}
+
+func (d *DB) set(key string, timeout, staleTimeout time.Duration, data any) {
+	now := time.Now()
+
+	value := &item{
+		uTime: now.Add(timeout),
+		sTime: now.Add(timeout + staleTimeout),
 		data:  data,
 	}
+
+	if old, ok := d.data[key]; ok {
+		value.elem = old.elem
+		d.order.MoveToFront(value.elem)
+	} else {
+		value.elem = d.order.PushFront(key)
+	}
+
+	d.data[key] = value
+
+	for d.limit > 0 && len(d.data) > d.limit {
+		d.remove(d.order.Back().Value.(string))
+	}
+}
+
+func (d *DB) remove(key string) {
+	if value, ok := d.data[key]; ok {
+		d.order.Remove(value.elem)
+		delete(d.data, key)
+	}
+}
+
+func (d *DB) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.data)
 }
 
 func (d *DB) Get(key string) any {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	if value, ok := d.data[key]; !ok {
+	value, ok := d.data[key]
+	if !ok {
+		metrics.ObserveCache(d.name, metrics.CacheMiss)
 		return nil
-	} else {
-		if value.uTime.After(time.Now()) {
-			return value.data
-		} else {
-			delete(d.data, key)
-			return nil
+	}
+
+	now := time.Now()
+
+	if value.uTime.After(now) {
+		d.order.MoveToFront(value.elem)
+		metrics.ObserveCache(d.name, metrics.CacheHit)
+		return value.data
+	}
+
+	if !value.sTime.After(now) {
+		d.remove(key)
+	}
+
+	metrics.ObserveCache(d.name, metrics.CacheMiss)
+
+	return nil
+}
+
+func (d *DB) Load(ctx context.Context, key string, timeout, staleTimeout time.Duration, load Loader) (data any, stale bool, err error) {
+	if data, stale, ok := d.lookup(ctx, key, timeout, staleTimeout, load); ok {
+		return data, stale, nil
+	}
+
+	data, err = load(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	d.SetStale(key, timeout, staleTimeout, data)
+
+	return data, false, nil
+}
+
+func (d *DB) lookup(ctx context.Context, key string, timeout, staleTimeout time.Duration, load Loader) (any, bool, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	value, ok := d.data[key]
+	if !ok {
+		metrics.ObserveCache(d.name, metrics.CacheMiss)
+		return nil, false, false
+	}
+
+	now := time.Now()
+
+	if value.uTime.After(now) {
+		d.order.MoveToFront(value.elem)
+		metrics.ObserveCache(d.name, metrics.CacheHit)
+		return value.data, false, true
+	}
+
+	if !value.sTime.After(now) {
+		d.remove(key)
+		metrics.ObserveCache(d.name, metrics.CacheMiss)
+		return nil, false, false
+	}
+
+	d.order.MoveToFront(value.elem)
+	metrics.ObserveCache(d.name, metrics.CacheStale)
+
+	if !value.refresh {
+		value.refresh = true
+		go d.refresh(context.WithoutCancel(ctx), key, timeout, staleTimeout, load)
+	}
+
+	return value.data, value.err != nil, true
+}
+
+func (d *DB) refresh(ctx context.Context, key string, timeout, staleTimeout time.Duration, load Loader) {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	data, err := load(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil {
+		if value, ok := d.data[key]; ok {
+			value.err = err
+			value.refresh = false
 		}
+
+		return
 	}
+
+	d.set(key, timeout, staleTimeout, data)
+}
+
+func (d *DB) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.data = make(map[string]*item)
+	d.order.Init()
 }
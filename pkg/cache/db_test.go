@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimitedDB(t *testing.T) {
+	db := NewLimitedDB("test", 2)
+
+	db.Set("a", time.Minute, 1)
+	db.Set("b", time.Minute, 2)
+
+	if db.Get("a") != 1 {
+		t.Fatal("a is missing")
+	}
+
+	db.Set("c", time.Minute, 3)
+
+	if db.Len() != 2 {
+		t.Fatalf("len = %d, want 2", db.Len())
+	}
+
+	if db.Get("b") != nil {
+		t.Error("least recently used key b was not evicted")
+	}
+
+	if db.Get("a") != 1 || db.Get("c") != 3 {
+		t.Error("recently used keys were evicted")
+	}
+
+	db.Set("a", time.Minute, 4)
+
+	if db.Len() != 2 || db.Get("a") != 4 {
+		t.Error("overwriting a key changed the size or kept the old value")
+	}
+
+	db.Flush()
+
+	if db.Len() != 0 || db.Get("a") != nil {
+		t.Error("flush kept data")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	db := NewDB("test")
+
+	db.Set("a", -time.Second, 1)
+
+	if db.Get("a") != nil {
+		t.Error("expired key was returned")
+	}
+
+	if db.Len() != 0 {
+		t.Error("expired key was not removed")
+	}
+}
+
+func TestLoadStale(t *testing.T) {
+	db := NewDB("test")
+	db.SetStale("a", -time.Second, time.Minute, 1)
+
+	refreshed := make(chan struct{})
+
+	data, stale, err := db.Load(context.Background(), "a", time.Minute, time.Minute, func(context.Context) (any, error) {
+		defer close(refreshed)
+		return 2, nil
+	})
+	if err != nil || data != 1 || stale {
+		t.Fatalf("Load = %v, %v, %v; want 1, false, nil", data, stale, err)
+	}
+
+	<-refreshed
+
+	deadline := time.Now().Add(time.Second)
+
+	for db.Get("a") != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh did not store the new value")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	db := NewDB("test")
+	want := errors.New("boom")
+
+	_, _, err := db.Load(context.Background(), "a", time.Minute, 0, func(context.Context) (any, error) {
+		return nil, want
+	})
+	if !errors.Is(err, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+
+	if db.Len() != 0 {
+		t.Error("failed load was cached")
+	}
+}
+
+type ctxKey struct{}
+
+func TestRefreshContext(t *testing.T) {
+	db := NewDB("test")
+	db.SetStale("a", -time.Second, time.Minute, 1)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "req-1"))
+	refreshed := make(chan context.Context, 1)
+
+	_, _, err := db.Load(ctx, "a", time.Minute, time.Minute, func(ctx context.Context) (any, error) {
+		refreshed <- ctx
+		return 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	refreshCtx := <-refreshed
+
+	if refreshCtx.Value(ctxKey{}) != "req-1" {
+		t.Error("refresh lost the caller context values")
+	}
+
+	if _, ok := refreshCtx.Deadline(); !ok {
+		t.Error("refresh has no timeout")
+	}
+}
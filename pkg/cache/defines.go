@@ -0,0 +1,7 @@
+package cache
+
+import "time"
+
+const (
+	refreshTimeout = time.Second * 30
+)
@@ -0,0 +1,22 @@
+package cassette
+
+import "os"
+
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+
+const (
+	modeEnv = "CASSETTE_MODE"
+)
+
+type Mode int
+
+func ModeFromEnv() Mode {
+	if os.Getenv(modeEnv) == "record" {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
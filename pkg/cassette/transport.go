@@ -0,0 +1,158 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+func NewTransport(path string, mode Mode, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	obj := &Transport{
+		mu:   new(sync.Mutex),
+		path: path,
+		mode: mode,
+		next: next,
+		used: make(map[int]bool),
+	}
+
+	if mode == ModeRecord {
+		return obj, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &obj.cassette); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+
+	return obj, nil
+}
+
+type Transport struct {
+	mu       *sync.Mutex
+	path     string
+	mode     Mode
+	next     http.RoundTripper
+	cassette Cassette
+	used     map[int]bool
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeRecord {
+		return t.record(req)
+	}
+
+	return t.replay(req)
+}
+
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(t.path, append(data, '\n'), 0o644)
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	rsp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+		},
+		Response: Response{
+			StatusCode: rsp.StatusCode,
+			Header:     headerOf(rsp.Header),
+			Body:       string(body),
+		},
+	})
+	t.mu.Unlock()
+
+	return newResponse(req, rsp.StatusCode, rsp.Header, body), nil
+}
+
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, row := range t.cassette.Interactions {
+		if t.used[i] || row.Request.Method != req.Method || row.Request.URL != req.URL.String() {
+			continue
+		}
+
+		t.used[i] = true
+
+		return newResponse(req, row.Response.StatusCode, row.Response.Header, []byte(row.Response.Body)), nil
+	}
+
+	return nil, fmt.Errorf("cassette %s: no interaction for %s %s", t.path, req.Method, req.URL)
+}
+
+func newResponse(req *http.Request, statusCode int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func headerOf(header http.Header) http.Header {
+	result := make(http.Header)
+
+	for key, values := range header {
+		if strings.EqualFold(key, "Content-Type") {
+			result[key] = values
+		}
+	}
+
+	return result
+}
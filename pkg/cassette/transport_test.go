@@ -0,0 +1,129 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string, string) {
+	t.Helper()
+
+	rsp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rsp.StatusCode, rsp.Header.Get("Content-Type"), string(body)
+}
+
+func TestRecordReplay(t *testing.T) {
+	calls := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "secret=1")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"n":` + r.URL.Query().Get("n") + `}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "nested", "cassette.json")
+
+	rt, err := NewTransport(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: rt}
+
+	for _, n := range []string{"1", "2", "1"} {
+		if status, _, body := get(t, client, srv.URL+"/v1?n="+n); status != http.StatusAccepted || body != `{"n":`+n+`}` {
+			t.Fatalf("record = %d %s", status, body)
+		}
+	}
+
+	if err = rt.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if rt, err = NewTransport(path, ModeReplay, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rt.cassette.Interactions) != 3 {
+		t.Fatalf("interactions = %d, want 3", len(rt.cassette.Interactions))
+	}
+
+	if header := rt.cassette.Interactions[0].Response.Header; header.Get("Set-Cookie") != "" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("recorded headers = %v, want only Content-Type", header)
+	}
+
+	client = &http.Client{Transport: rt}
+	recorded := calls
+
+	for _, n := range []string{"2", "1", "1"} {
+		status, contentType, body := get(t, client, srv.URL+"/v1?n="+n)
+
+		if status != http.StatusAccepted || contentType != "application/json" || body != `{"n":`+n+`}` {
+			t.Errorf("replay n=%s = %d %q %s", n, status, contentType, body)
+		}
+	}
+
+	if calls != recorded {
+		t.Errorf("replay reached the upstream %d times", calls-recorded)
+	}
+
+	if _, err = client.Get(srv.URL + "/v1?n=1"); err == nil || !strings.Contains(err.Error(), "no interaction") {
+		t.Errorf("replay after interactions ran out: err = %v", err)
+	}
+
+	if _, err = client.Get(srv.URL + "/v2"); err == nil {
+		t.Error("replay of an unknown request succeeded")
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := NewTransport(filepath.Join(dir, "missing.json"), ModeReplay, nil); err == nil {
+		t.Error("replaying a missing cassette succeeded")
+	}
+
+	rt, err := NewTransport(filepath.Join(dir, "missing.json"), ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("record mode needs no cassette: %v", err)
+	}
+
+	if rt.Save() != nil {
+		t.Error("saving an empty cassette failed")
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(modeEnv, "record")
+
+	if ModeFromEnv() != ModeRecord {
+		t.Error("CASSETTE_MODE=record did not select record mode")
+	}
+
+	t.Setenv(modeEnv, "")
+
+	if ModeFromEnv() != ModeReplay {
+		t.Error("default mode is not replay")
+	}
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"exchanges/pkg/apikey"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/logger"
+	"exchanges/pkg/quality"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Server    Server              `json:"server"`
+	History   History             `json:"history"`
+	Quality   Quality             `json:"quality"`
+	Auth      Auth                `json:"auth"`
+	Exchanges map[string]Exchange `json:"exchanges"`
+}
+
+type Server struct {
+	Addr            string   `json:"addr"`
+	GRPCAddr        string   `json:"grpc_addr,omitempty"`
+	LogFile         string   `json:"log_file,omitempty"`
+	LogMaxSize      int      `json:"log_max_size_mb,omitempty"`
+	LogMaxBackups   int      `json:"log_max_backups,omitempty"`
+	LogLevel        string   `json:"log_level,omitempty"`
+	LogFormat       string   `json:"log_format,omitempty"`
+	ReqTimeout      Duration `json:"request_timeout"`
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+	ReadyRequireAll bool     `json:"ready_require_all"`
+}
+
+type History struct {
+	Dir       string   `json:"dir,omitempty"`
+	Interval  Duration `json:"interval"`
+	Retention Duration `json:"retention"`
+	Books     []string `json:"books,omitempty"`
+}
+
+type Quality struct {
+	Mode         string   `json:"mode,omitempty"`
+	MaxAge       Duration `json:"max_age"`
+	MaxDeviation float64  `json:"max_deviation"`
+	MinVenues    int      `json:"min_venues,omitempty"`
+	ReferenceTTL Duration `json:"reference_ttl,omitempty"`
+	Enforce      []string `json:"enforce,omitempty"`
+}
+
+type Auth struct {
+	Keys []APIKey `json:"keys,omitempty"`
+}
+
+type APIKey struct {
+	Name       string   `json:"name"`
+	KeyHash    string   `json:"key_hash"`
+	RateLimit  float64  `json:"rate_limit,omitempty"`
+	RateBurst  int      `json:"rate_burst,omitempty"`
+	Routes     []string `json:"routes,omitempty"`
+	Exchanges  []string `json:"exchanges,omitempty"`
+	Admin      bool     `json:"admin,omitempty"`
+	MaxStreams int      `json:"max_streams,omitempty"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:            defaultAddr,
+			ReqTimeout:      Duration(defaultReqTimeout),
+			ShutdownTimeout: Duration(defaultShutdownTimeout),
+		},
+		History: History{
+			Interval:  Duration(defaultHistoryInterval),
+			Retention: Duration(defaultHistoryRetention),
+		},
+		Quality: Quality{
+			Mode:         quality.ModeFlag,
+			MaxAge:       Duration(defaultQualityMaxAge),
+			MaxDeviation: defaultQualityDeviation,
+		},
+		Exchanges: make(map[string]Exchange),
+	}
+}
+
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if len(path) > 0 {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		for _, exchangeID := range exchange.DefaultNames() {
+			cfg.Exchanges[exchangeID] = Exchange{}
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+
+	if len(c.Server.Addr) == 0 {
+		errs = append(errs, errors.New("server.addr is empty"))
+	}
+
+	if c.Server.ReqTimeout <= 0 {
+		errs = append(errs, errors.New("server.request_timeout must be positive"))
+	}
+
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+
+	if len(c.Server.LogLevel) > 0 {
+		var level slog.Level
+
+		if err := level.UnmarshalText([]byte(c.Server.LogLevel)); err != nil {
+			errs = append(errs, fmt.Errorf("server.log_level: %w", err))
+		}
+	}
+
+	if format := strings.ToLower(c.Server.LogFormat); len(format) > 0 && format != logger.FormatText && format != logger.FormatJSON {
+		errs = append(errs, fmt.Errorf("server.log_format: must be %s or %s", logger.FormatText, logger.FormatJSON))
+	}
+
+	if len(c.History.Dir) > 0 && c.History.Interval <= 0 {
+		errs = append(errs, errors.New("history.interval must be positive"))
+	}
+
+	for _, book := range c.History.Books {
+		if exchangeID, pairID, ok := strings.Cut(book, ":"); !ok || len(exchangeID) == 0 || len(pairID) == 0 {
+			errs = append(errs, fmt.Errorf("history.books: %q must look like exchange:pair", book))
+		}
+	}
+
+	if mode := c.Quality.Mode; len(mode) > 0 && !slices.Contains(quality.Modes, mode) {
+		errs = append(errs, fmt.Errorf("quality.mode: must be one of %s", strings.Join(quality.Modes, ", ")))
+	}
+
+	if c.Quality.MaxAge < 0 || c.Quality.MaxDeviation < 0 || c.Quality.MinVenues < 0 || c.Quality.ReferenceTTL < 0 {
+		errs = append(errs, errors.New("quality: max_age, max_deviation, min_venues and reference_ttl must not be negative"))
+	}
+
+	for _, rule := range c.Quality.Enforce {
+		if !slices.Contains(quality.Rules, rule) {
+			errs = append(errs, fmt.Errorf("quality.enforce: unknown rule %q, known: %s", rule, strings.Join(quality.Rules, ", ")))
+		}
+	}
+
+	names := make(map[string]bool)
+
+	for i, key := range c.Auth.Keys {
+		switch {
+		case len(key.Name) == 0:
+			errs = append(errs, fmt.Errorf("auth.keys[%d]: name is empty", i))
+		case names[key.Name]:
+			errs = append(errs, fmt.Errorf("auth.keys[%d]: duplicate name %s", i, key.Name))
+		}
+
+		names[key.Name] = true
+
+		if _, err := apikey.ParseHash(key.KeyHash); err != nil {
+			errs = append(errs, fmt.Errorf("auth.keys[%d]: %w", i, err))
+		}
+
+		if key.RateLimit < 0 || key.MaxStreams < 0 {
+			errs = append(errs, fmt.Errorf("auth.keys[%d]: rate_limit and max_streams must not be negative", i))
+		}
+	}
+
+	for _, exchangeID := range c.exchangeIDs() {
+		value := c.Exchanges[exchangeID]
+
+		if adapter := value.Adapter; !exchange.Registered(adapter) && (len(adapter) > 0 || !exchange.Registered(exchangeID)) {
+			errs = append(errs, fmt.Errorf("exchanges.%s: unknown exchange", exchangeID))
+			continue
+		}
+
+		if _, err := value.New(exchangeID); err != nil {
+			errs = append(errs, fmt.Errorf("exchanges.%s: %w", exchangeID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s Server) Logger() logger.Config {
+	return logger.Config{
+		File:       s.LogFile,
+		MaxSize:    int64(s.LogMaxSize) << 20,
+		MaxBackups: s.LogMaxBackups,
+		Level:      s.LogLevel,
+		Format:     s.LogFormat,
+	}
+}
+
+func (q Quality) Config() quality.Config {
+	return quality.Config{
+		Mode:         q.Mode,
+		MaxAge:       time.Duration(q.MaxAge),
+		MaxDeviation: q.MaxDeviation,
+		MinVenues:    q.MinVenues,
+		ReferenceTTL: time.Duration(q.ReferenceTTL),
+		Enforce:      q.Enforce,
+	}
+}
+
+func (a Auth) APIKeys() []apikey.Key {
+	result := make([]apikey.Key, 0, len(a.Keys))
+
+	for _, key := range a.Keys {
+		result = append(result, apikey.Key{
+			Name:       key.Name,
+			Hash:       key.KeyHash,
+			RateLimit:  key.RateLimit,
+			RateBurst:  key.RateBurst,
+			Routes:     key.Routes,
+			Exchanges:  key.Exchanges,
+			Admin:      key.Admin,
+			MaxStreams: key.MaxStreams,
+		})
+	}
+
+	return result
+}
+
+func (c *Config) EnabledExchanges() []string {
+	var result []string
+
+	for _, exchangeID := range c.exchangeIDs() {
+		if c.Exchanges[exchangeID].IsEnabled() {
+			result = append(result, exchangeID)
+		}
+	}
+
+	return result
+}
+
+func (c *Config) HistoryBooks() map[string][]string {
+	result := make(map[string][]string)
+
+	for _, book := range c.History.Books {
+		if exchangeID, pairID, ok := strings.Cut(book, ":"); ok {
+			result[exchangeID] = append(result[exchangeID], pairID)
+		}
+	}
+
+	return result
+}
+
+func (c *Config) exchangeIDs() []string {
+	var result []string
+
+	for exchangeID := range c.Exchanges {
+		result = append(result, exchangeID)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+const (
+	envPrefix = "EXCHANGES"
+
+	defaultAddr             = ":8080"
+	defaultReqTimeout       = time.Second * 15
+	defaultShutdownTimeout  = time.Minute
+	defaultHistoryInterval  = time.Minute
+	defaultHistoryRetention = time.Hour * 24 * 7
+	defaultQualityMaxAge    = time.Second * 30
+	defaultQualityDeviation = 0.05
+)
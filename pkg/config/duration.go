@@ -0,0 +1,5 @@
+package config
+
+import "exchanges/pkg/duration"
+
+type Duration = duration.Duration
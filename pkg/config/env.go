@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType   = reflect.TypeOf(Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+func (c *Config) applyEnv(lookup func(key string) (string, bool)) error {
+	v := reflect.ValueOf(c).Elem()
+
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Struct {
+			continue
+		}
+
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+
+		if _, err := applyEnv(envPrefix+"_"+envName(name), v.Field(i), lookup); err != nil {
+			return err
+		}
+	}
+
+	for _, exchangeID := range c.envExchangeIDs() {
+		value, listed := c.Exchanges[exchangeID]
+
+		changed, err := applyEnv(envPrefix+"_"+envName(exchangeID), reflect.ValueOf(&value).Elem(), lookup)
+		if err != nil {
+			return err
+		}
+
+		if changed && (listed || value.Enabled != nil && *value.Enabled) {
+			c.Exchanges[exchangeID] = value
+		}
+	}
+
+	return nil
+}
+
+// envExchangeIDs returns the registered exchanges and the ones defined in the config, such as generic venues.
+func (c *Config) envExchangeIDs() []string {
+	result := exchange.Names()
+
+	for _, exchangeID := range c.exchangeIDs() {
+		if !slices.Contains(result, exchangeID) {
+			result = append(result, exchangeID)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return '_'
+	}, strings.ToUpper(name))
+}
+
+func applyEnv(prefix string, v reflect.Value, lookup func(key string) (string, bool)) (bool, error) {
+	var changed bool
+
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if len(name) == 0 || name == "-" {
+			continue
+		}
+
+		key := prefix + "_" + envName(name)
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Struct {
+			ok, err := applyEnv(key, field, lookup)
+			if err != nil {
+				return false, err
+			}
+
+			changed = changed || ok
+
+			continue
+		}
+
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+
+		if err := setValue(field, raw); err != nil {
+			return false, fmt.Errorf("%s: %w", key, err)
+		}
+
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == rawMessageType {
+		if !json.Valid([]byte(raw)) {
+			return fmt.Errorf("invalid json")
+		}
+
+		v.SetBytes([]byte(raw))
+
+		return nil
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+
+		if err := setValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+
+		v.Set(elem)
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			result := reflect.New(v.Type())
+
+			if err := json.Unmarshal([]byte(raw), result.Interface()); err != nil {
+				return err
+			}
+
+			v.Set(result.Elem())
+
+			return nil
+		}
+
+		var result []string
+
+		for _, row := range strings.Split(raw, ",") {
+			if row = strings.TrimSpace(row); len(row) > 0 {
+				result = append(result, row)
+			}
+		}
+
+		v.Set(reflect.ValueOf(result))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
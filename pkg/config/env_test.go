@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyEnv(t *testing.T) {
+	cfg := Default()
+	cfg.Exchanges["bybit_spec"] = Exchange{Adapter: "generic"}
+
+	env := map[string]string{
+		"EXCHANGES_SERVER_ADDR":                  ":9090",
+		"EXCHANGES_HISTORY_BOOKS":                "bybit:BTCUSDT, okx:BTC-USDT",
+		"EXCHANGES_QUALITY_MODE":                 "reject",
+		"EXCHANGES_QUALITY_MAX_DEVIATION":        "0.1",
+		"EXCHANGES_QUALITY_ENFORCE":              "crossed,stale",
+		"EXCHANGES_AUTH_KEYS":                    `[{"name":"ops","key_hash":"abc","admin":true}]`,
+		"EXCHANGES_BYBIT_SPEC_BASE_URL":          "http://127.0.0.1:8081",
+		"EXCHANGES_BYBIT_SPEC_BREAKER_COOL_DOWN": "1m",
+		"EXCHANGES_BYBIT_SPEC_PARAMS":            `{"pairs":{}}`,
+		"EXCHANGES_UNLISTED_BASE_URL":            "http://127.0.0.1:8082",
+	}
+
+	err := cfg.applyEnv(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Addr != ":9090" {
+		t.Errorf("server.addr = %q", cfg.Server.Addr)
+	}
+
+	if want := []string{"bybit:BTCUSDT", "okx:BTC-USDT"}; !reflect.DeepEqual(cfg.History.Books, want) {
+		t.Errorf("history.books = %v, want %v", cfg.History.Books, want)
+	}
+
+	if cfg.Quality.Mode != "reject" || cfg.Quality.MaxDeviation != 0.1 || !reflect.DeepEqual(cfg.Quality.Enforce, []string{"crossed", "stale"}) {
+		t.Errorf("quality = %+v", cfg.Quality)
+	}
+
+	if want := []APIKey{{Name: "ops", KeyHash: "abc", Admin: true}}; !reflect.DeepEqual(cfg.Auth.Keys, want) {
+		t.Errorf("auth.keys = %+v, want %+v", cfg.Auth.Keys, want)
+	}
+
+	spec := cfg.Exchanges["bybit_spec"]
+
+	if spec.Adapter != "generic" || spec.BaseURL != "http://127.0.0.1:8081" || spec.Breaker.CoolDown != Duration(time.Minute) || string(spec.Params) != `{"pairs":{}}` {
+		t.Errorf("bybit_spec = %+v", spec)
+	}
+
+	if _, ok := cfg.Exchanges["unlisted"]; ok {
+		t.Error("an unknown exchange was added from the environment")
+	}
+}
+
+func TestApplyEnvErrors(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{key: "EXCHANGES_SERVER_REQUEST_TIMEOUT", value: "soon"},
+		{key: "EXCHANGES_QUALITY_MIN_VENUES", value: "two"},
+		{key: "EXCHANGES_AUTH_KEYS", value: "ops"},
+		{key: "EXCHANGES_BYBIT_SPEC_PARAMS", value: "{"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.key, func(t *testing.T) {
+			cfg := Default()
+			cfg.Exchanges["bybit_spec"] = Exchange{Adapter: "generic"}
+
+			err := cfg.applyEnv(func(key string) (string, bool) {
+				return tt.value, key == tt.key
+			})
+			if err == nil {
+				t.Fatalf("%s=%s: want error", tt.key, tt.value)
+			}
+		})
+	}
+}
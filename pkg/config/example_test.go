@@ -0,0 +1,46 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExampleBybitSpec(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "config.example.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg Config
+
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, ok := cfg.Exchanges["bybit_spec"]
+	if !ok || entry.Adapter != "generic" {
+		t.Fatalf("config.example.json has no generic bybit_spec entry: %+v", entry)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join("..", "exchange", "generic", "testdata", "bybit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got, want any
+
+	if err = json.Unmarshal(entry.Params, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = json.Unmarshal(fixture, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Error("bybit_spec params in config.example.json differ from pkg/exchange/generic/testdata/bybit.json")
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"exchanges/pkg/breaker"
+	"exchanges/pkg/exchange"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+)
+
+type Exchange struct {
+	Enabled   *bool    `json:"enabled,omitempty"`
+	Adapter   string   `json:"adapter,omitempty"`
+	BaseURL   string   `json:"base_url,omitempty"`
+	Proxy     string   `json:"proxy,omitempty"`
+	UserAgent string   `json:"user_agent,omitempty"`
+	Timeout   Duration `json:"timeout,omitempty"`
+	RateLimit float64  `json:"rate_limit,omitempty"`
+	RateBurst int      `json:"rate_burst,omitempty"`
+	TLS       TLS      `json:"tls"`
+	Breaker   Breaker  `json:"breaker"`
+	Retry     Retry    `json:"retry"`
+
+	PairsCache   Cache `json:"pairs_cache"`
+	TickersCache Cache `json:"tickers_cache"`
+	Debug        bool  `json:"debug,omitempty"`
+	WireLogBody  int   `json:"wire_log_body,omitempty"`
+
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type Cache struct {
+	Timeout      Duration `json:"timeout,omitempty"`
+	StaleTimeout Duration `json:"stale_timeout,omitempty"`
+}
+
+type Breaker struct {
+	FailureThreshold int      `json:"failure_threshold,omitempty"`
+	CoolDown         Duration `json:"cool_down,omitempty"`
+	HalfOpenRequests int      `json:"half_open_requests,omitempty"`
+}
+
+type Retry struct {
+	MaxAttempts int      `json:"max_attempts,omitempty"`
+	BaseDelay   Duration `json:"base_delay,omitempty"`
+	MaxDelay    Duration `json:"max_delay,omitempty"`
+}
+
+type TLS struct {
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+func (e Exchange) IsEnabled() bool {
+	return e.Enabled == nil || *e.Enabled
+}
+
+func (e Exchange) New(exchangeID string) (exchange.Exchange, error) {
+	opts, err := e.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := exchangeID
+
+	if len(e.Adapter) > 0 {
+		adapter = e.Adapter
+		opts = append(opts, exchange.WithID(exchangeID))
+	}
+
+	obj, err := exchange.New(adapter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.GetID() != exchangeID {
+		return nil, fmt.Errorf("adapter %s does not support custom exchange ids", adapter)
+	}
+
+	return obj, nil
+}
+
+func (e Exchange) Options() ([]exchange.Option, error) {
+	var opts []exchange.Option
+
+	if len(e.BaseURL) > 0 {
+		if _, err := url.ParseRequestURI(e.BaseURL); err != nil {
+			return nil, fmt.Errorf("base_url: %w", err)
+		}
+
+		opts = append(opts, exchange.WithBaseURL(e.BaseURL))
+	}
+
+	if len(e.Proxy) > 0 {
+		proxy, err := url.Parse(e.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: %w", err)
+		}
+
+		opts = append(opts, exchange.WithProxy(proxy))
+	}
+
+	if len(e.UserAgent) > 0 {
+		opts = append(opts, exchange.WithUserAgent(e.UserAgent))
+	}
+
+	if e.Timeout > 0 {
+		opts = append(opts, exchange.WithTimeout(time.Duration(e.Timeout)))
+	}
+
+	if e.RateLimit > 0 {
+		opts = append(opts, exchange.WithRateLimit(e.RateLimit, e.RateBurst))
+	}
+
+	if e.PairsCache.Timeout > 0 {
+		opts = append(opts, exchange.WithPairsCache(time.Duration(e.PairsCache.Timeout), time.Duration(e.PairsCache.StaleTimeout)))
+	}
+
+	if e.TickersCache.Timeout > 0 {
+		opts = append(opts, exchange.WithTickersCache(time.Duration(e.TickersCache.Timeout), time.Duration(e.TickersCache.StaleTimeout)))
+	}
+
+	if e.Breaker != (Breaker{}) {
+		opts = append(opts, exchange.WithBreaker(breaker.Config{
+			FailureThreshold: e.Breaker.FailureThreshold,
+			CoolDown:         time.Duration(e.Breaker.CoolDown),
+			HalfOpenRequests: e.Breaker.HalfOpenRequests,
+		}))
+	}
+
+	if e.Retry != (Retry{}) {
+		opts = append(opts, exchange.WithRetry(exchange.RetryPolicy{
+			MaxAttempts: e.Retry.MaxAttempts,
+			BaseDelay:   time.Duration(e.Retry.BaseDelay),
+			MaxDelay:    time.Duration(e.Retry.MaxDelay),
+		}))
+	}
+
+	if e.Debug {
+		opts = append(opts, exchange.WithDebug(true))
+	}
+
+	if e.WireLogBody != 0 {
+		opts = append(opts, exchange.WithWireLogBody(e.WireLogBody))
+	}
+
+	if len(e.Params) > 0 {
+		opts = append(opts, exchange.WithParams(e.Params))
+	}
+
+	tlsConfig, err := e.TLS.config()
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+
+	if tlsConfig != nil {
+		opts = append(opts, exchange.WithTLSConfig(tlsConfig))
+	}
+
+	return opts, nil
+}
+
+func (t TLS) config() (*tls.Config, error) {
+	if t == (TLS{}) {
+		return nil, nil
+	}
+
+	result := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if len(t.CAFile) > 0 {
+		data, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates in %s", t.CAFile)
+		}
+
+		result.RootCAs = pool
+	}
+
+	if len(t.CertFile) > 0 || len(t.KeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package bybit
+
+import (
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"testing"
+	"time"
+)
+
+func TestConformance(t *testing.T) {
+	exchangetest.Run(t, exchangetest.Suite{
+		New: func(t *testing.T, baseURL string) exchange.Exchange {
+			return NewAPI(
+				exchange.WithBaseURL(baseURL),
+				exchange.WithRateLimit(0, 1),
+				exchange.WithRetry(exchange.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+			)
+		},
+		Routes: []exchangetest.Route{
+			{
+				Path: "/v5/market/instruments-info",
+				Body: `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[{"symbol":"BTCUSDT","baseCoin":"BTC","quoteCoin":"USDT","status":"Trading"},{"symbol":"ETHUSDT","baseCoin":"ETH","quoteCoin":"USDT","status":"Trading"},{"symbol":"NEWUSDT","baseCoin":"NEW","quoteCoin":"USDT","status":"PreLaunch"},{"symbol":"BADUSDT","baseCoin":"","quoteCoin":"USDT","status":"Trading"}]},"time":1695200000000}`,
+			},
+			{
+				Path: "/v5/market/tickers",
+				Body: `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[{"symbol":"BTCUSDT","ask1Price":"26750.01","bid1Price":"26750","volume24h":"1520.318"},{"symbol":"ETHUSDT","ask1Price":"1630.5","bid1Price":"1630.4","volume24h":"20110"},{"symbol":"BADUSDT","ask1Price":"1","bid1Price":"0"}]},"time":1695200000000}`,
+			},
+			{
+				Path:  "/v5/market/orderbook",
+				Query: map[string]string{"symbol": "BTCUSDT"},
+				Body:  `{"retCode":0,"retMsg":"OK","result":{"s":"BTCUSDT","a":[["26750.5","0.3"],["26750.01","1.2"]],"b":[["26749.99","2.5"],["26750","0.8"]],"ts":1695200000000,"u":123456},"time":1695200000001}`,
+			},
+			{
+				Path:  "/v5/market/orderbook",
+				Query: map[string]string{"symbol": "NOPEUSDT"},
+				Body:  `{"retCode":10001,"retMsg":"Not supported symbols","result":{},"time":1695200000000}`,
+			},
+			{
+				Path:  "/v5/market/orderbook",
+				Query: map[string]string{"symbol": "BADUSDT"},
+				Body:  `{"retCode":0,"retMsg":"OK","result":{"s":"BADUSDT","a":[["1.01","5"]],"b":[["1","5","0"]],"ts":1695200000000,"u":1},"time":1695200000001}`,
+			},
+		},
+		Pair:          "BTCUSDT",
+		UnknownPair:   "NOPEUSDT",
+		MalformedPair: "BADUSDT",
+	})
+}
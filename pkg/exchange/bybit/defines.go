@@ -1,14 +1,29 @@
 package bybit
 
 import (
+	"exchanges/pkg/exchange"
 	"time"
 )
 
 const (
-	baseURL = "https://api.bybit.com"
-	doPause = time.Second
+	exchangeID = "bybit"
+
+	baseURL   = "https://api.bybit.com"
+	rateLimit = 1
+	rateBurst = 1
+
+	pairsCacheTimeout        = time.Minute * 5
+	pairsCacheStaleTimeout   = time.Hour
+	tickersCacheTimeout      = time.Second * 5
+	tickersCacheStaleTimeout = time.Second * 30
 )
 
 var (
-	Debug bool
+	retCodeErrors = map[int]error{
+		10001:  exchange.ErrInvalidArgument,
+		10006:  exchange.ErrRateLimited,
+		10016:  exchange.ErrUpstreamUnavailable,
+		10018:  exchange.ErrRateLimited,
+		170121: exchange.ErrPairNotFound,
+	}
 )
@@ -3,24 +3,39 @@ package bybit
 import (
 	"context"
 	"encoding/json"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/metrics"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 func (a *API) doPublicGET(ctx context.Context, endpoint string, payload url.Values, result any) error {
-	a.mu.Lock()
-	defer func() {
-		go func() {
-			time.Sleep(doPause)
-			a.mu.Unlock()
-		}()
-	}()
+	brk := a.breakers.Get(endpoint)
+
+	if err := brk.Allow(); err != nil {
+		return exchange.WrapError(exchange.ErrCircuitOpen, exchangeID, fmt.Errorf("%s: %w", endpoint, err))
+	}
+
+	return exchange.RetryGuarded(ctx, a.retry, brk, a.health, func() error {
+		return a.get(ctx, endpoint, payload, result)
+	})
+}
+
+func (a *API) get(ctx context.Context, endpoint string, payload url.Values, result any) error {
+	start := time.Now()
+
+	if err := a.limiter.Wait(ctx); err != nil {
+		return exchange.WrapError(exchange.TransportKind(err), exchangeID, err)
+	}
+
+	metrics.ObserveRateLimitWait(exchangeID, time.Since(start))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+endpoint, nil)
 	if err != nil {
 		return err
 	}
@@ -29,13 +44,24 @@ func (a *API) doPublicGET(ctx context.Context, endpoint string, payload url.Valu
 
 	req.Header.Add("Accept", "application/json")
 
-	return a.do(req, result)
+	if len(a.userAgent) > 0 {
+		req.Header.Set("User-Agent", a.userAgent)
+	}
+
+	start = time.Now()
+	err = a.do(req, result)
+
+	metrics.ObserveUpstream(exchangeID, endpoint, exchange.ResultCode(err), time.Since(start))
+
+	return err
 }
 
 func (a *API) do(req *http.Request, result any) error {
+	start := time.Now()
+
 	rsp, err := a.cli.Do(req)
 	if err != nil {
-		return err
+		return exchange.WrapError(exchange.TransportKind(err), exchangeID, err)
 	}
 
 	defer func() {
@@ -44,12 +70,10 @@ func (a *API) do(req *http.Request, result any) error {
 
 	body, err := io.ReadAll(rsp.Body)
 	if err != nil {
-		return err
+		return exchange.WrapError(exchange.TransportKind(err), exchangeID, err)
 	}
 
-	if Debug {
-		log.Printf("%s %s %d\n%s", req.Method, req.URL, rsp.StatusCode, string(body))
-	}
+	a.wireLog.Log(req.Context(), req, rsp, body, time.Since(start))
 
 	var checkErr struct {
 		RetCode int    `json:"retCode"`
@@ -58,23 +82,61 @@ func (a *API) do(req *http.Request, result any) error {
 
 	if err = json.Unmarshal(body, &checkErr); err == nil {
 		if checkErr.RetCode != 0 || checkErr.RetMsg != "OK" {
-			return fmt.Errorf("%s %s %d [%d: %s]",
-				req.Method,
-				req.URL,
-				rsp.StatusCode,
-				checkErr.RetCode,
-				checkErr.RetMsg,
-			)
+			return exchange.NewError(
+				retCodeKind(checkErr.RetCode, checkErr.RetMsg, rsp.StatusCode),
+				exchangeID,
+				strconv.Itoa(checkErr.RetCode),
+				fmt.Sprintf("%s %s %d [%d: %s]",
+					req.Method,
+					req.URL,
+					rsp.StatusCode,
+					checkErr.RetCode,
+					checkErr.RetMsg,
+				),
+			).WithRetryAfter(exchange.RetryAfter(rsp))
 		}
 	}
 
 	if rsp.StatusCode != 200 {
-		return fmt.Errorf("%s %s %d", req.Method, req.URL, rsp.StatusCode)
+		return exchange.NewError(
+			statusKind(rsp.StatusCode),
+			exchangeID,
+			"",
+			fmt.Sprintf("%s %s %d", req.Method, req.URL, rsp.StatusCode),
+		).WithRetryAfter(exchange.RetryAfter(rsp))
 	}
 
 	if result == nil {
 		return nil
 	}
 
-	return json.Unmarshal(body, result)
+	if err = json.Unmarshal(body, result); err != nil {
+		return exchange.WrapError(exchange.ErrBadResponse, exchangeID, err)
+	}
+
+	return nil
+}
+
+func retCodeKind(retCode int, retMsg string, statusCode int) error {
+	if retCode == 10001 && strings.Contains(strings.ToLower(retMsg), "symbol") {
+		return exchange.ErrPairNotFound
+	}
+
+	if kind, ok := retCodeErrors[retCode]; ok {
+		return kind
+	}
+
+	if statusCode != 200 {
+		return statusKind(statusCode)
+	}
+
+	return exchange.ErrBadResponse
+}
+
+func statusKind(statusCode int) error {
+	if statusCode == http.StatusForbidden {
+		return exchange.ErrRateLimited
+	}
+
+	return exchange.StatusKind(statusCode)
 }
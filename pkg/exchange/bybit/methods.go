@@ -6,20 +6,38 @@ import (
 	"fmt"
 	"github.com/shopspring/decimal"
 	"net/url"
-	"time"
 )
 
 func (a *API) GetID() string {
-	return "bybit"
+	return exchangeID
+}
+
+func (a *API) FlushCache() {
+	a.db.Flush()
+}
+
+func (a *API) Health() exchange.Health {
+	result := a.health.Health()
+	result.SetBreakers(a.breakers.States())
+
+	return result
+}
+
+func (a *API) SetWireLog(enabled bool) {
+	a.wireLog.SetEnabled(enabled)
+}
+
+func (a *API) Ping(ctx context.Context) error {
+	return a.doPublicGET(ctx, "/v5/market/time", nil, nil)
 }
 
 func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
-	pairs, err := a.getPairs(ctx)
+	pairs, pairsStale, err := a.getPairs(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tickers, err := a.getTickers(ctx)
+	tickers, tickersStale, err := a.getTickers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +52,8 @@ func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
 				QuoteAsset: row.QuoteAsset,
 				Ask:        askBid[0],
 				Bid:        askBid[1],
+				Volume:     askBid[2],
+				Stale:      pairsStale || tickersStale,
 			})
 		}
 	}
@@ -41,14 +61,20 @@ func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
 	return result, nil
 }
 
-func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, error) {
+func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, bool, error) {
 	cacheKey := "getPairs"
-	cacheTimeout := time.Minute * 5
 
-	if cache, ok := a.db.Get(cacheKey).([]exchange.Pair); ok {
-		return cache, nil
+	data, stale, err := a.db.Load(ctx, cacheKey, a.pairsCache.Timeout, a.pairsCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchPairs(ctx)
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
+	return data.([]exchange.Pair), stale, nil
+}
+
+func (a *API) fetchPairs(ctx context.Context) ([]exchange.Pair, error) {
 	endpoint := "/v5/market/instruments-info"
 
 	payload := url.Values{}
@@ -95,12 +121,23 @@ func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, error) {
 		})
 	}
 
-	a.db.Set(cacheKey, cacheTimeout, result)
-
 	return result, nil
 }
 
-func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
+func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, bool, error) {
+	cacheKey := "getTickers"
+
+	data, stale, err := a.db.Load(ctx, cacheKey, a.tickersCache.Timeout, a.tickersCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchTickers(ctx)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data.(map[string][]decimal.Decimal), stale, nil
+}
+
+func (a *API) fetchTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
 	endpoint := "/v5/market/tickers"
 
 	payload := url.Values{}
@@ -112,6 +149,7 @@ func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, err
 				Symbol string          `json:"symbol"`
 				Ask    decimal.Decimal `json:"ask1Price"`
 				Bid    decimal.Decimal `json:"bid1Price"`
+				Volume decimal.Decimal `json:"volume24h"`
 			} `json:"list"`
 		} `json:"result"`
 	}
@@ -135,13 +173,17 @@ func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, err
 			continue
 		}
 
-		result[row.Symbol] = []decimal.Decimal{row.Ask, row.Bid}
+		result[row.Symbol] = []decimal.Decimal{row.Ask, row.Bid, row.Volume}
 	}
 
 	return result, nil
 }
 
 func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBook, error) {
+	if len(pairID) == 0 {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrInvalidArgument, exchangeID, "", "empty pair id")
+	}
+
 	endpoint := "/v5/market/orderbook"
 
 	payload := url.Values{}
@@ -151,9 +193,12 @@ func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBo
 
 	var temp struct {
 		Result struct {
-			Symbol string              `json:"s"`
-			Ask    [][]decimal.Decimal `json:"a"`
-			Bid    [][]decimal.Decimal `json:"b"`
+			Symbol    string              `json:"s"`
+			Ask       [][]decimal.Decimal `json:"a"`
+			Bid       [][]decimal.Decimal `json:"b"`
+			Timestamp int64               `json:"ts"`
+			UpdateID  int64               `json:"u"`
+			Sequence  int64               `json:"seq"`
 		} `json:"result"`
 	}
 
@@ -163,15 +208,21 @@ func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBo
 
 	for _, asks := range temp.Result.Ask {
 		if len(asks) != 2 {
-			return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp)
+			return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp))
 		}
 	}
 
 	for _, bids := range temp.Result.Bid {
 		if len(bids) != 2 {
-			return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp)
+			return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp))
 		}
 	}
 
-	return exchange.OrderBook{Ask: temp.Result.Ask, Bid: temp.Result.Bid}, nil
+	return exchange.OrderBook{
+		Ask:       temp.Result.Ask,
+		Bid:       temp.Result.Bid,
+		Timestamp: temp.Result.Timestamp,
+		Sequence:  temp.Result.Sequence,
+		UpdateID:  temp.Result.UpdateID,
+	}, nil
 }
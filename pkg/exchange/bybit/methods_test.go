@@ -0,0 +1,166 @@
+package bybit
+
+import (
+	"context"
+	"exchanges/pkg/breaker"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     []exchange.Pair
+		wantErr  string
+		wantKind error
+	}{
+		{
+			name: "get_pairs_ok",
+			want: []exchange.Pair{
+				{Id: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26750.01"), Bid: exchangetest.Dec("26750"), Volume: exchangetest.Dec("1520.318")},
+				{Id: "SOLUSDT", BaseAsset: "SOL", QuoteAsset: "USDT", Ask: exchangetest.Dec("19.825"), Bid: exchangetest.Dec("19.82"), Volume: exchangetest.Dec("84211.7")},
+			},
+		},
+		{
+			name: "get_pairs_retry_ok",
+			want: []exchange.Pair{
+				{Id: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26750.01"), Bid: exchangetest.Dec("26750"), Volume: exchangetest.Dec("1520.318")},
+				{Id: "SOLUSDT", BaseAsset: "SOL", QuoteAsset: "USDT", Ask: exchangetest.Dec("19.825"), Bid: exchangetest.Dec("19.82"), Volume: exchangetest.Dec("84211.7")},
+			},
+		},
+		{
+			name:     "get_pairs_retcode_error",
+			wantErr:  "[10001: params error]",
+			wantKind: exchange.ErrInvalidArgument,
+		},
+		{
+			name:     "get_pairs_http_error",
+			wantErr:  "instruments-info?category=spot 403",
+			wantKind: exchange.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAPI(exchangetest.Cassette(t, tt.name)...).GetPairs(context.Background())
+			exchangetest.CheckErr(t, err, tt.wantErr, tt.wantKind, exchangeID)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     exchange.OrderBook
+		wantErr  string
+		wantKind error
+	}{
+		{
+			name: "get_order_book_ok",
+			want: exchange.OrderBook{
+				Ask: [][]decimal.Decimal{{exchangetest.Dec("26750.01"), exchangetest.Dec("1.2")}, {exchangetest.Dec("26750.5"), exchangetest.Dec("0.3")}},
+				Bid: [][]decimal.Decimal{{exchangetest.Dec("26750"), exchangetest.Dec("0.8")}, {exchangetest.Dec("26749.99"), exchangetest.Dec("2.5")}},
+
+				Timestamp: 1695200000000,
+				Sequence:  7961638724,
+				UpdateID:  1800123,
+			},
+		},
+		{
+			name:     "get_order_book_retcode_error",
+			wantErr:  "[10001: Not supported symbols]",
+			wantKind: exchange.ErrPairNotFound,
+		},
+		{
+			name:     "get_order_book_parse_error",
+			wantErr:  "json parse error",
+			wantKind: exchange.ErrBadResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAPI(exchangetest.Cassette(t, tt.name)...).GetOrderBook(context.Background(), "BTCUSDT")
+			exchangetest.CheckErr(t, err, tt.wantErr, tt.wantKind, exchangeID)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOrderBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallerDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	obj := NewAPI(
+		exchange.WithBaseURL(srv.URL),
+		exchange.WithRateLimit(0, 1),
+		exchange.WithBreaker(breaker.Config{FailureThreshold: 1}),
+		exchange.WithRetry(exchange.RetryPolicy{MaxAttempts: 1}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	if _, err := obj.GetOrderBook(ctx, "BTCUSDT"); err == nil {
+		t.Fatal("request past the caller deadline succeeded")
+	}
+
+	for endpoint, state := range obj.breakers.States() {
+		if state != breaker.StateClosed {
+			t.Errorf("breaker %s = %s after the caller deadline, want closed", endpoint, state)
+		}
+	}
+}
+
+func TestHangingUpstream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	obj := NewAPI(
+		exchange.WithBaseURL(srv.URL),
+		exchange.WithRateLimit(0, 1),
+		exchange.WithTimeout(time.Millisecond*20),
+		exchange.WithBreaker(breaker.Config{FailureThreshold: 1}),
+		exchange.WithRetry(exchange.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond * 2}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	if _, err := obj.GetOrderBook(ctx, "BTCUSDT"); err == nil {
+		t.Fatal("request to a hanging upstream succeeded")
+	}
+
+	if state := obj.breakers.States()["/v5/market/orderbook"]; state != breaker.StateOpen {
+		t.Errorf("breaker = %s after the upstream timed out, want open", state)
+	}
+
+	if health := obj.Health(); health.Status == exchange.HealthUnknown || health.Failures == 0 {
+		t.Errorf("health = %+v after the upstream timed out", health)
+	}
+}
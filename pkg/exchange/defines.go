@@ -0,0 +1,16 @@
+package exchange
+
+import "time"
+
+const (
+	defaultTimeout     = time.Second * 10
+	defaultWireLogBody = 4096
+
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = time.Millisecond * 100
+	defaultRetryMaxDelay  = time.Second * 2
+	banRetryAfter         = time.Hour
+
+	healthDownFailures   = 3
+	healthDegradedWindow = time.Minute
+)
@@ -0,0 +1,178 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrPairNotFound        = errors.New("pair not found")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrBadResponse         = errors.New("upstream bad response")
+	ErrTimeout             = errors.New("timeout")
+	ErrInvalidArgument     = errors.New("invalid argument")
+	ErrCircuitOpen         = errors.New("circuit open")
+	ErrBadData             = errors.New("bad market data")
+)
+
+const (
+	CodePairNotFound        = "pair_not_found"
+	CodeRateLimited         = "rate_limited"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeBadResponse         = "upstream_bad_response"
+	CodeTimeout             = "timeout"
+	CodeInvalidArgument     = "invalid_argument"
+	CodeCircuitOpen         = "circuit_open"
+	CodeBadData             = "bad_data"
+)
+
+var errorCodes = []struct {
+	kind error
+	code string
+}{
+	{ErrPairNotFound, CodePairNotFound},
+	{ErrRateLimited, CodeRateLimited},
+	{ErrUpstreamUnavailable, CodeUpstreamUnavailable},
+	{ErrBadResponse, CodeBadResponse},
+	{ErrTimeout, CodeTimeout},
+	{ErrInvalidArgument, CodeInvalidArgument},
+	{ErrCircuitOpen, CodeCircuitOpen},
+	{ErrBadData, CodeBadData},
+}
+
+type Error struct {
+	Kind         error
+	Exchange     string
+	UpstreamCode string
+	Message      string
+	RetryAfter   time.Duration
+	Err          error
+}
+
+func NewError(kind error, exchangeID, upstreamCode, message string) *Error {
+	return &Error{
+		Kind:         kind,
+		Exchange:     exchangeID,
+		UpstreamCode: upstreamCode,
+		Message:      message,
+	}
+}
+
+func WrapError(kind error, exchangeID string, err error) *Error {
+	return &Error{
+		Kind:     kind,
+		Exchange: exchangeID,
+		Message:  err.Error(),
+		Err:      err,
+	}
+}
+
+func (e *Error) WithRetryAfter(delay time.Duration) *Error {
+	e.RetryAfter = delay
+	return e
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+func ErrorCode(err error) string {
+	for _, row := range errorCodes {
+		if errors.Is(err, row.kind) {
+			return row.code
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+
+	return ""
+}
+
+func KindByCode(code string) (error, bool) {
+	for _, row := range errorCodes {
+		if row.code == code {
+			return row.kind, true
+		}
+	}
+
+	return nil, false
+}
+
+func TransportKind(err error) error {
+	var netErr net.Error
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+
+	return ErrUpstreamUnavailable
+}
+
+func StatusKind(statusCode int) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusBadRequest:
+		return ErrInvalidArgument
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case statusCode >= http.StatusInternalServerError:
+		return ErrUpstreamUnavailable
+	default:
+		return ErrBadResponse
+	}
+}
+
+// RetryAfter returns how long to wait before retrying rsp, from its Retry-After header.
+// Venues answer 403 and 418 to banned clients, which are not worth retrying.
+func RetryAfter(rsp *http.Response) time.Duration {
+	if rsp.StatusCode == http.StatusForbidden || rsp.StatusCode == http.StatusTeapot {
+		return banRetryAfter
+	}
+
+	value := rsp.Header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+
+	return 0
+}
+
+func ResultCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	if code := ErrorCode(err); len(code) > 0 {
+		return code
+	}
+
+	return "error"
+}
+
+func IsFailure(err error) bool {
+	return err != nil && !errors.Is(err, ErrPairNotFound) && !errors.Is(err, ErrInvalidArgument)
+}
@@ -0,0 +1,81 @@
+package exchangetest
+
+import (
+	"errors"
+	"exchanges/pkg/cassette"
+	"exchanges/pkg/exchange"
+	"github.com/shopspring/decimal"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Cassette returns adapter options backed by testdata/<name>.json, recorded when CASSETTE_MODE=record.
+func Cassette(t *testing.T, name string) []exchange.Option {
+	t.Helper()
+
+	rt, err := cassette.NewTransport(filepath.Join("testdata", name+".json"), cassette.ModeFromEnv(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := rt.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return options(rt)
+}
+
+// Replay returns adapter options that only replay the cassette at path.
+func Replay(t *testing.T, path string) []exchange.Option {
+	t.Helper()
+
+	rt, err := cassette.NewTransport(path, cassette.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return options(rt)
+}
+
+func options(rt *cassette.Transport) []exchange.Option {
+	return []exchange.Option{
+		exchange.WithTransport(rt),
+		exchange.WithRateLimit(0, 1),
+		exchange.WithRetry(exchange.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	}
+}
+
+func Dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+// CheckErr fails unless err contains wantErr, matches wantKind and is an *exchange.Error of exchangeID.
+func CheckErr(t *testing.T, err error, wantErr string, wantKind error, exchangeID string) {
+	t.Helper()
+
+	if len(wantErr) == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return
+	}
+
+	if err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Fatalf("error = %v, want %q", err, wantErr)
+	}
+
+	if !errors.Is(err, wantKind) {
+		t.Fatalf("error = %v, want kind %v", err, wantKind)
+	}
+
+	var e *exchange.Error
+
+	if !errors.As(err, &e) || e.Exchange != exchangeID {
+		t.Fatalf("error = %#v, want *exchange.Error for %s", err, exchangeID)
+	}
+}
@@ -0,0 +1,83 @@
+package exchangetest
+
+import (
+	"exchanges/pkg/exchange"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+func CheckPairs(pairs []exchange.Pair) error {
+	if len(pairs) == 0 {
+		return fmt.Errorf("no pairs")
+	}
+
+	seen := make(map[string]bool)
+
+	for _, row := range pairs {
+		switch {
+		case len(row.Id) == 0:
+			return fmt.Errorf("pair %+v: empty id", row)
+		case seen[row.Id]:
+			return fmt.Errorf("pair %s: duplicate id", row.Id)
+		case len(row.BaseAsset) == 0:
+			return fmt.Errorf("pair %s: empty base asset", row.Id)
+		case len(row.QuoteAsset) == 0:
+			return fmt.Errorf("pair %s: empty quote asset", row.Id)
+		case !row.Ask.IsPositive():
+			return fmt.Errorf("pair %s: ask %s is not positive", row.Id, row.Ask)
+		case !row.Bid.IsPositive():
+			return fmt.Errorf("pair %s: bid %s is not positive", row.Id, row.Bid)
+		case row.Ask.LessThan(row.Bid):
+			return fmt.Errorf("pair %s: ask %s is below bid %s", row.Id, row.Ask, row.Bid)
+		case row.Volume.IsNegative():
+			return fmt.Errorf("pair %s: volume %s is negative", row.Id, row.Volume)
+		}
+
+		seen[row.Id] = true
+	}
+
+	return nil
+}
+
+// CheckOrderBook expects a book that has already been sorted with OrderBook.Sort.
+func CheckOrderBook(book exchange.OrderBook) error {
+	if len(book.Ask) == 0 || len(book.Bid) == 0 {
+		return fmt.Errorf("empty book side: %d asks, %d bids", len(book.Ask), len(book.Bid))
+	}
+
+	if err := checkLevels("ask", book.Ask, func(prev, next decimal.Decimal) bool { return prev.LessThan(next) }); err != nil {
+		return err
+	}
+
+	if err := checkLevels("bid", book.Bid, func(prev, next decimal.Decimal) bool { return prev.GreaterThan(next) }); err != nil {
+		return err
+	}
+
+	if bid, ask := book.Bid[0][0], book.Ask[0][0]; !bid.LessThan(ask) {
+		return fmt.Errorf("crossed book: best bid %s, best ask %s", bid, ask)
+	}
+
+	return nil
+}
+
+func checkLevels(side string, levels [][]decimal.Decimal, ordered func(prev, next decimal.Decimal) bool) error {
+	for i, row := range levels {
+		if len(row) != 2 {
+			return fmt.Errorf("%s %d: %d values, want price and amount", side, i, len(row))
+		}
+
+		if !row[0].IsPositive() {
+			return fmt.Errorf("%s %d: price %s is not positive", side, i, row[0])
+		}
+
+		if !row[1].IsPositive() {
+			return fmt.Errorf("%s %d: amount %s is not positive", side, i, row[1])
+		}
+
+		if i > 0 && !ordered(levels[i-1][0], row[0]) {
+			return fmt.Errorf("%s %d: price %s is out of order after %s", side, i, row[0], levels[i-1][0])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package exchangetest
+
+import (
+	"context"
+	"errors"
+	"exchanges/pkg/exchange"
+	"testing"
+)
+
+type Suite struct {
+	// New builds the adapter against the fake upstream.
+	New func(t *testing.T, baseURL string) exchange.Exchange
+	// Routes must answer GetPairs, GetOrderBook for Pair, the venue's
+	// not-found error for UnknownPair and, when set, a book with a
+	// malformed bid level for MalformedPair.
+	Routes        []Route
+	Pair          string
+	UnknownPair   string
+	MalformedPair string
+}
+
+func Run(t *testing.T, s Suite) {
+	t.Helper()
+
+	upstream := NewUpstream(t, s.Routes)
+	obj := s.New(t, upstream.URL)
+	ctx := context.Background()
+
+	t.Run("pairs", func(t *testing.T) {
+		pairs, err := obj.GetPairs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = CheckPairs(pairs); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("order_book", func(t *testing.T) {
+		book, err := obj.GetOrderBook(ctx, s.Pair)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		book.Sort()
+
+		if err = CheckOrderBook(book); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("unknown_pair", func(t *testing.T) {
+		_, err := obj.GetOrderBook(ctx, s.UnknownPair)
+		checkKind(t, obj, err, exchange.ErrPairNotFound)
+	})
+
+	t.Run("malformed_book", func(t *testing.T) {
+		if len(s.MalformedPair) == 0 {
+			t.Skip("no malformed pair")
+		}
+
+		_, err := obj.GetOrderBook(ctx, s.MalformedPair)
+		checkKind(t, obj, err, exchange.ErrBadResponse)
+	})
+
+	t.Run("empty_pair", func(t *testing.T) {
+		_, err := obj.GetOrderBook(ctx, "")
+		checkKind(t, obj, err, exchange.ErrInvalidArgument)
+	})
+}
+
+func checkKind(t *testing.T, obj exchange.Exchange, err error, kind error) {
+	t.Helper()
+
+	if !errors.Is(err, kind) {
+		t.Fatalf("error = %v, want kind %v", err, kind)
+	}
+
+	var e *exchange.Error
+
+	if !errors.As(err, &e) || e.Exchange != obj.GetID() {
+		t.Fatalf("error = %#v, want *exchange.Error for %s", err, obj.GetID())
+	}
+}
@@ -0,0 +1,59 @@
+package exchangetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type Route struct {
+	Path   string
+	Query  map[string]string
+	Status int
+	Body   string
+}
+
+// NewUpstream serves the first route whose path and query parameters match the request.
+func NewUpstream(t *testing.T, routes []Route) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, row := range routes {
+			if row.match(r) {
+				status := row.Status
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(row.Body))
+
+				return
+			}
+		}
+
+		t.Errorf("unexpected upstream request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func (r Route) match(req *http.Request) bool {
+	if req.URL.Path != r.Path {
+		return false
+	}
+
+	query := req.URL.Query()
+
+	for key, value := range r.Query {
+		if query.Get(key) != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,115 @@
+package exchange
+
+import (
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+)
+
+const (
+	SortByID     = "id"
+	SortBySpread = "spread"
+	SortByVolume = "volume"
+	SortByPrice  = "price"
+)
+
+var SortKeys = []string{SortByID, SortBySpread, SortByVolume, SortByPrice}
+
+type PairFilter struct {
+	Base   string
+	Quote  string
+	Prefix string
+	Search string
+	SortBy string
+	Desc   bool
+}
+
+func ValidSortKey(key string) bool {
+	for _, row := range SortKeys {
+		if row == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f PairFilter) Apply(pairs []Pair) []Pair {
+	result := make([]Pair, 0, len(pairs))
+
+	for _, row := range pairs {
+		if f.match(row) {
+			result = append(result, row)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return f.Compare(result[i], f.SortKey(result[j]), result[j].Id) < 0
+	})
+
+	return result
+}
+
+// Compare orders pair against the position of a pair with sort key and id in f's order.
+func (f PairFilter) Compare(pair Pair, key decimal.Decimal, id string) int {
+	cmp := f.SortKey(pair).Cmp(key)
+	if cmp == 0 {
+		cmp = strings.Compare(pair.Id, id)
+	}
+
+	if f.Desc {
+		return -cmp
+	}
+
+	return cmp
+}
+
+func (f PairFilter) match(pair Pair) bool {
+	if len(f.Base) > 0 && !strings.EqualFold(pair.BaseAsset, f.Base) {
+		return false
+	}
+
+	if len(f.Quote) > 0 && !strings.EqualFold(pair.QuoteAsset, f.Quote) {
+		return false
+	}
+
+	id := strings.ToUpper(pair.Id)
+
+	if len(f.Prefix) > 0 && !strings.HasPrefix(id, strings.ToUpper(f.Prefix)) {
+		return false
+	}
+
+	if search := strings.ToUpper(f.Search); len(search) > 0 && !strings.Contains(id, search) &&
+		!strings.Contains(strings.ToUpper(pair.BaseAsset), search) &&
+		!strings.Contains(strings.ToUpper(pair.QuoteAsset), search) {
+		return false
+	}
+
+	return true
+}
+
+func (f PairFilter) SortKey(pair Pair) decimal.Decimal {
+	switch f.SortBy {
+	case SortBySpread:
+		return pair.Spread()
+	case SortByVolume:
+		return pair.Volume
+	case SortByPrice:
+		return pair.Mid()
+	default:
+		return decimal.Zero
+	}
+}
+
+func (p Pair) Mid() decimal.Decimal {
+	return p.Ask.Add(p.Bid).Div(decimal.NewFromInt(2))
+}
+
+func (p Pair) Spread() decimal.Decimal {
+	mid := p.Mid()
+	if mid.IsZero() {
+		return decimal.Zero
+	}
+
+	return p.Ask.Sub(p.Bid).Div(mid)
+}
@@ -0,0 +1,114 @@
+package exchange
+
+import (
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+)
+
+func testPair(id, base, quote, ask, bid string, volume int64) Pair {
+	return Pair{
+		Id:         id,
+		BaseAsset:  base,
+		QuoteAsset: quote,
+		Ask:        decimal.RequireFromString(ask),
+		Bid:        decimal.RequireFromString(bid),
+		Volume:     decimal.NewFromInt(volume),
+	}
+}
+
+func TestPairFilterApply(t *testing.T) {
+	pairs := []Pair{
+		testPair("ETHUSDT", "ETH", "USDT", "10.1", "10", 50),
+		testPair("BTCUSDT", "BTC", "USDT", "101", "99", 5),
+		testPair("ETHBTC", "ETH", "BTC", "0.06", "0.05", 5),
+		testPair("DOGEUSDT", "DOGE", "USDT", "0.1", "0.1", 500),
+		testPair("FREE", "FREE", "USDT", "0", "0", 0),
+	}
+
+	tests := []struct {
+		name   string
+		filter PairFilter
+		want   []string
+	}{
+		{name: "id", filter: PairFilter{SortBy: SortByID}, want: []string{"BTCUSDT", "DOGEUSDT", "ETHBTC", "ETHUSDT", "FREE"}},
+		{name: "empty_sort", filter: PairFilter{}, want: []string{"BTCUSDT", "DOGEUSDT", "ETHBTC", "ETHUSDT", "FREE"}},
+		{name: "id_desc", filter: PairFilter{SortBy: SortByID, Desc: true}, want: []string{"FREE", "ETHUSDT", "ETHBTC", "DOGEUSDT", "BTCUSDT"}},
+		{name: "volume_ties_by_id", filter: PairFilter{SortBy: SortByVolume}, want: []string{"FREE", "BTCUSDT", "ETHBTC", "ETHUSDT", "DOGEUSDT"}},
+		{name: "volume_desc_reverses_ties", filter: PairFilter{SortBy: SortByVolume, Desc: true}, want: []string{"DOGEUSDT", "ETHUSDT", "ETHBTC", "BTCUSDT", "FREE"}},
+		{name: "price", filter: PairFilter{SortBy: SortByPrice}, want: []string{"FREE", "ETHBTC", "DOGEUSDT", "ETHUSDT", "BTCUSDT"}},
+		{name: "spread_zero_mid", filter: PairFilter{SortBy: SortBySpread}, want: []string{"DOGEUSDT", "FREE", "ETHUSDT", "BTCUSDT", "ETHBTC"}},
+		{name: "base_case_insensitive", filter: PairFilter{Base: "eth"}, want: []string{"ETHBTC", "ETHUSDT"}},
+		{name: "quote", filter: PairFilter{Quote: "Btc"}, want: []string{"ETHBTC"}},
+		{name: "base_and_quote", filter: PairFilter{Base: "ETH", Quote: "USDT"}, want: []string{"ETHUSDT"}},
+		{name: "prefix", filter: PairFilter{Prefix: "eth"}, want: []string{"ETHBTC", "ETHUSDT"}},
+		{name: "prefix_not_infix", filter: PairFilter{Prefix: "USDT"}, want: []string{}},
+		{name: "search_id", filter: PairFilter{Search: "hbt"}, want: []string{"ETHBTC"}},
+		{name: "search_asset", filter: PairFilter{Search: "btc"}, want: []string{"BTCUSDT", "ETHBTC"}},
+		{name: "no_match", filter: PairFilter{Base: "SOL"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]Pair(nil), pairs...)
+			result := tt.filter.Apply(input)
+
+			got := make([]string, 0, len(result))
+			for _, row := range result {
+				got = append(got, row.Id)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(input, pairs) {
+				t.Error("Apply() changed its input")
+			}
+
+			for i := 1; i < len(result); i++ {
+				if tt.filter.Compare(result[i], tt.filter.SortKey(result[i-1]), result[i-1].Id) <= 0 {
+					t.Errorf("Compare() does not order %s after %s", result[i].Id, result[i-1].Id)
+				}
+			}
+		})
+	}
+}
+
+func TestPairSpread(t *testing.T) {
+	tests := []struct {
+		pair   Pair
+		mid    string
+		spread string
+	}{
+		{pair: testPair("A", "A", "B", "101", "99", 0), mid: "100", spread: "0.02"},
+		{pair: testPair("A", "A", "B", "1", "1", 0), mid: "1", spread: "0"},
+		{pair: testPair("A", "A", "B", "0", "0", 0), mid: "0", spread: "0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pair.Mid(); !got.Equal(decimal.RequireFromString(tt.mid)) {
+			t.Errorf("%s/%s Mid() = %s, want %s", tt.pair.Ask, tt.pair.Bid, got, tt.mid)
+		}
+
+		if got := tt.pair.Spread(); !got.Equal(decimal.RequireFromString(tt.spread)) {
+			t.Errorf("%s/%s Spread() = %s, want %s", tt.pair.Ask, tt.pair.Bid, got, tt.spread)
+		}
+	}
+}
+
+func TestValidSortKey(t *testing.T) {
+	for _, key := range SortKeys {
+		if !ValidSortKey(key) {
+			t.Errorf("ValidSortKey(%q) = false", key)
+		}
+	}
+
+	for _, key := range []string{"", "ID", "name"} {
+		if ValidSortKey(key) {
+			t.Errorf("ValidSortKey(%q) = true", key)
+		}
+	}
+}
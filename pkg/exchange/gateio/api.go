@@ -1,21 +1,60 @@
 package gateio
 
 import (
+	"exchanges/pkg/breaker"
 	"exchanges/pkg/cache"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/logger"
+	"exchanges/pkg/ratelimit"
 	"net/http"
-	"sync"
 )
 
-func NewAPI() *API {
+func init() {
+	exchange.Register(exchangeID, func(opts ...exchange.Option) (exchange.Exchange, error) {
+		return NewAPI(opts...), nil
+	})
+}
+
+func NewAPI(opts ...exchange.Option) *API {
+	cfg := exchange.NewOptions(exchange.Options{
+		BaseURL:   baseURL,
+		RateLimit: rateLimit,
+		RateBurst: rateBurst,
+		PairsCache: exchange.CacheTimeout{
+			Timeout:      pairsCacheTimeout,
+			StaleTimeout: pairsCacheStaleTimeout,
+		},
+		TickersCache: exchange.CacheTimeout{
+			Timeout:      tickersCacheTimeout,
+			StaleTimeout: tickersCacheStaleTimeout,
+		},
+	}, opts...)
+
 	return &API{
-		mu:  new(sync.Mutex),
-		cli: new(http.Client),
-		db:  cache.NewDB(),
+		cli:          cfg.HTTPClient(),
+		db:           cache.NewDB(exchangeID),
+		limiter:      ratelimit.NewLimiter(cfg.RateLimit, cfg.RateBurst),
+		breakers:     breaker.NewSet(cfg.Breaker),
+		retry:        cfg.Retry,
+		health:       exchange.NewHealthTracker(),
+		baseURL:      cfg.BaseURL,
+		userAgent:    cfg.UserAgent,
+		pairsCache:   cfg.PairsCache,
+		tickersCache: cfg.TickersCache,
+		wireLog:      logger.NewWireLog(exchangeID, cfg.Debug, cfg.WireLogBody),
 	}
 }
 
 type API struct {
-	mu  *sync.Mutex
-	cli *http.Client
-	db  *cache.DB
+	cli          *http.Client
+	db           *cache.DB
+	limiter      *ratelimit.Limiter
+	breakers     *breaker.Set
+	retry        exchange.RetryPolicy
+	health       *exchange.HealthTracker
+	baseURL      string
+	userAgent    string
+	pairsCache   exchange.CacheTimeout
+	tickersCache exchange.CacheTimeout
+	wireLog      *logger.WireLog
 }
@@ -0,0 +1,50 @@
+package gateio
+
+import (
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConformance(t *testing.T) {
+	exchangetest.Run(t, exchangetest.Suite{
+		New: func(t *testing.T, baseURL string) exchange.Exchange {
+			return NewAPI(
+				exchange.WithBaseURL(baseURL+"/api/v4"),
+				exchange.WithRateLimit(0, 1),
+				exchange.WithRetry(exchange.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+			)
+		},
+		Routes: []exchangetest.Route{
+			{
+				Path: "/api/v4/spot/currency_pairs",
+				Body: `[{"id":"BTC_USDT","base":"BTC","quote":"USDT","trade_status":"tradable"},{"id":"ETH_USDT","base":"ETH","quote":"USDT","trade_status":"tradable"},{"id":"DOGE_USDT","base":"DOGE","quote":"USDT","trade_status":"tradable"},{"id":"OLD_USDT","base":"OLD","quote":"USDT","trade_status":"untradable"}]`,
+			},
+			{
+				Path: "/api/v4/spot/tickers",
+				Body: `[{"currency_pair":"BTC_USDT","lowest_ask":"26752.4","highest_bid":"26752.3","base_volume":"987.123"},{"currency_pair":"ETH_USDT","lowest_ask":"1630.6","highest_bid":"1630.55","base_volume":"15001.5"},{"currency_pair":"DOGE_USDT","lowest_ask":"","highest_bid":"0.0625"},{"currency_pair":"OLD_USDT","lowest_ask":"1","highest_bid":"0.9"}]`,
+			},
+			{
+				Path:  "/api/v4/spot/order_book",
+				Query: map[string]string{"currency_pair": "BTC_USDT"},
+				Body:  `{"current":1695200000123,"update":1695200000120,"id":987654321,"asks":[["26752.5","1"],["26752.4","0.25"]],"bids":[["26752.1","3"],["26752.3","0.4"]]}`,
+			},
+			{
+				Path:   "/api/v4/spot/order_book",
+				Query:  map[string]string{"currency_pair": "NOPE_USDT"},
+				Status: http.StatusBadRequest,
+				Body:   `{"label":"INVALID_CURRENCY_PAIR","message":"Invalid currency pair NOPE_USDT"}`,
+			},
+			{
+				Path:  "/api/v4/spot/order_book",
+				Query: map[string]string{"currency_pair": "BAD_USDT"},
+				Body:  `{"current":1695200000123,"update":1695200000120,"id":1,"asks":[["1.01","5"]],"bids":[["1","5","0"]]}`,
+			},
+		},
+		Pair:          "BTC_USDT",
+		UnknownPair:   "NOPE_USDT",
+		MalformedPair: "BAD_USDT",
+	})
+}
@@ -1,14 +1,32 @@
 package gateio
 
 import (
+	"exchanges/pkg/exchange"
 	"time"
 )
 
 const (
-	baseURL = "https://api.gateio.ws/api/v4"
-	doPause = time.Second
+	exchangeID = "gateio"
+
+	baseURL   = "https://api.gateio.ws/api/v4"
+	rateLimit = 1
+	rateBurst = 1
+
+	pairsCacheTimeout        = time.Minute * 5
+	pairsCacheStaleTimeout   = time.Hour
+	tickersCacheTimeout      = time.Second * 5
+	tickersCacheStaleTimeout = time.Second * 30
 )
 
 var (
-	Debug bool
+	labelErrors = map[string]error{
+		"INVALID_CURRENCY":       exchange.ErrPairNotFound,
+		"INVALID_CURRENCY_PAIR":  exchange.ErrPairNotFound,
+		"INVALID_PARAM_VALUE":    exchange.ErrInvalidArgument,
+		"INVALID_ARGUMENT":       exchange.ErrInvalidArgument,
+		"MISSING_REQUIRED_PARAM": exchange.ErrInvalidArgument,
+		"TOO_MANY_REQUESTS":      exchange.ErrRateLimited,
+		"SERVER_ERROR":           exchange.ErrUpstreamUnavailable,
+		"TOO_BUSY":               exchange.ErrUpstreamUnavailable,
+	}
 )
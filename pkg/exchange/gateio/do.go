@@ -3,24 +3,37 @@ package gateio
 import (
 	"context"
 	"encoding/json"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/metrics"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"time"
 )
 
 func (a *API) doPublicGET(ctx context.Context, endpoint string, payload url.Values, result any) error {
-	a.mu.Lock()
-	defer func() {
-		go func() {
-			time.Sleep(doPause)
-			a.mu.Unlock()
-		}()
-	}()
+	brk := a.breakers.Get(endpoint)
+
+	if err := brk.Allow(); err != nil {
+		return exchange.WrapError(exchange.ErrCircuitOpen, exchangeID, fmt.Errorf("%s: %w", endpoint, err))
+	}
+
+	return exchange.RetryGuarded(ctx, a.retry, brk, a.health, func() error {
+		return a.get(ctx, endpoint, payload, result)
+	})
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+endpoint, nil)
+func (a *API) get(ctx context.Context, endpoint string, payload url.Values, result any) error {
+	start := time.Now()
+
+	if err := a.limiter.Wait(ctx); err != nil {
+		return exchange.WrapError(exchange.TransportKind(err), exchangeID, err)
+	}
+
+	metrics.ObserveRateLimitWait(exchangeID, time.Since(start))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+endpoint, nil)
 	if err != nil {
 		return err
 	}
@@ -29,13 +42,24 @@ func (a *API) doPublicGET(ctx context.Context, endpoint string, payload url.Valu
 
 	req.Header.Add("Accept", "application/json")
 
-	return a.do(req, result)
+	if len(a.userAgent) > 0 {
+		req.Header.Set("User-Agent", a.userAgent)
+	}
+
+	start = time.Now()
+	err = a.do(req, result)
+
+	metrics.ObserveUpstream(exchangeID, endpoint, exchange.ResultCode(err), time.Since(start))
+
+	return err
 }
 
 func (a *API) do(req *http.Request, result any) error {
+	start := time.Now()
+
 	rsp, err := a.cli.Do(req)
 	if err != nil {
-		return err
+		return exchange.WrapError(exchange.TransportKind(err), exchangeID, err)
 	}
 
 	defer func() {
@@ -44,12 +68,10 @@ func (a *API) do(req *http.Request, result any) error {
 
 	body, err := io.ReadAll(rsp.Body)
 	if err != nil {
-		return err
+		return exchange.WrapError(exchange.TransportKind(err), exchangeID, err)
 	}
 
-	if Debug {
-		log.Printf("%s %s %d\n%s", req.Method, req.URL, rsp.StatusCode, string(body))
-	}
+	a.wireLog.Log(req.Context(), req, rsp, body, time.Since(start))
 
 	if rsp.StatusCode != 200 {
 		checkErr := struct {
@@ -59,16 +81,38 @@ func (a *API) do(req *http.Request, result any) error {
 
 		if err = json.Unmarshal(body, &checkErr); err == nil {
 			if len(checkErr.Label) > 0 {
-				return fmt.Errorf("%s %s %d [%s]", req.Method, req.URL, rsp.StatusCode, checkErr.Label)
+				return exchange.NewError(
+					labelKind(checkErr.Label, rsp.StatusCode),
+					exchangeID,
+					checkErr.Label,
+					fmt.Sprintf("%s %s %d [%s]", req.Method, req.URL, rsp.StatusCode, checkErr.Label),
+				).WithRetryAfter(exchange.RetryAfter(rsp))
 			}
 		}
 
-		return fmt.Errorf("%s %s %d", req.Method, req.URL, rsp.StatusCode)
+		return exchange.NewError(
+			exchange.StatusKind(rsp.StatusCode),
+			exchangeID,
+			"",
+			fmt.Sprintf("%s %s %d", req.Method, req.URL, rsp.StatusCode),
+		).WithRetryAfter(exchange.RetryAfter(rsp))
 	}
 
 	if result == nil {
 		return nil
 	}
 
-	return json.Unmarshal(body, result)
+	if err = json.Unmarshal(body, result); err != nil {
+		return exchange.WrapError(exchange.ErrBadResponse, exchangeID, err)
+	}
+
+	return nil
+}
+
+func labelKind(label string, statusCode int) error {
+	if kind, ok := labelErrors[label]; ok {
+		return kind
+	}
+
+	return exchange.StatusKind(statusCode)
 }
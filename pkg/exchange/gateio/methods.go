@@ -6,20 +6,38 @@ import (
 	"fmt"
 	"github.com/shopspring/decimal"
 	"net/url"
-	"time"
 )
 
 func (a *API) GetID() string {
-	return "gateio"
+	return exchangeID
+}
+
+func (a *API) FlushCache() {
+	a.db.Flush()
+}
+
+func (a *API) Health() exchange.Health {
+	result := a.health.Health()
+	result.SetBreakers(a.breakers.States())
+
+	return result
+}
+
+func (a *API) SetWireLog(enabled bool) {
+	a.wireLog.SetEnabled(enabled)
+}
+
+func (a *API) Ping(ctx context.Context) error {
+	return a.doPublicGET(ctx, "/api/v4/spot/time", nil, nil)
 }
 
 func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
-	pairs, err := a.getPairs(ctx)
+	pairs, pairsStale, err := a.getPairs(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tickers, err := a.getTickers(ctx)
+	tickers, tickersStale, err := a.getTickers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +52,8 @@ func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
 				QuoteAsset: row.QuoteAsset,
 				Ask:        askBid[0],
 				Bid:        askBid[1],
+				Volume:     askBid[2],
+				Stale:      pairsStale || tickersStale,
 			})
 		}
 	}
@@ -41,14 +61,20 @@ func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
 	return result, nil
 }
 
-func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, error) {
+func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, bool, error) {
 	cacheKey := "getPairs"
-	cacheTimeout := time.Minute * 5
 
-	if cache, ok := a.db.Get(cacheKey).([]exchange.Pair); ok {
-		return cache, nil
+	data, stale, err := a.db.Load(ctx, cacheKey, a.pairsCache.Timeout, a.pairsCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchPairs(ctx)
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
+	return data.([]exchange.Pair), stale, nil
+}
+
+func (a *API) fetchPairs(ctx context.Context) ([]exchange.Pair, error) {
 	endpoint := "/spot/currency_pairs"
 
 	var temp []struct {
@@ -88,18 +114,30 @@ func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, error) {
 		})
 	}
 
-	a.db.Set(cacheKey, cacheTimeout, result)
-
 	return result, nil
 }
 
-func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
+func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, bool, error) {
+	cacheKey := "getTickers"
+
+	data, stale, err := a.db.Load(ctx, cacheKey, a.tickersCache.Timeout, a.tickersCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchTickers(ctx)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data.(map[string][]decimal.Decimal), stale, nil
+}
+
+func (a *API) fetchTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
 	endpoint := "/spot/tickers"
 
 	var temp []struct {
-		Id  string `json:"currency_pair"`
-		Ask string `json:"lowest_ask"`
-		Bid string `json:"highest_bid"`
+		Id     string `json:"currency_pair"`
+		Ask    string `json:"lowest_ask"`
+		Bid    string `json:"highest_bid"`
+		Volume string `json:"base_volume"`
 	}
 
 	if err := a.doPublicGET(ctx, endpoint, nil, &temp); err != nil {
@@ -131,22 +169,37 @@ func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, err
 			continue
 		}
 
-		result[row.Id] = []decimal.Decimal{ask, bid}
+		volume := decimal.Zero
+
+		if len(row.Volume) > 0 {
+			if volume, err = decimal.NewFromString(row.Volume); err != nil {
+				continue
+			}
+		}
+
+		result[row.Id] = []decimal.Decimal{ask, bid, volume}
 	}
 
 	return result, nil
 }
 
 func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBook, error) {
+	if len(pairID) == 0 {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrInvalidArgument, exchangeID, "", "empty pair id")
+	}
+
 	endpoint := "/spot/order_book"
 
 	payload := url.Values{}
 	payload.Set("currency_pair", pairID)
 	payload.Set("limit", "100")
+	payload.Set("with_id", "true")
 
 	var temp struct {
-		Asks [][]decimal.Decimal `json:"asks"`
-		Bids [][]decimal.Decimal `json:"bids"`
+		Id      int64               `json:"id"`
+		Current int64               `json:"current"`
+		Asks    [][]decimal.Decimal `json:"asks"`
+		Bids    [][]decimal.Decimal `json:"bids"`
 	}
 
 	if err := a.doPublicGET(ctx, endpoint, payload, &temp); err != nil {
@@ -155,15 +208,22 @@ func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBo
 
 	for _, asks := range temp.Asks {
 		if len(asks) != 2 {
-			return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp)
+			return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp))
 		}
 	}
 
-	for _, bids := range temp.Asks {
+	for _, bids := range temp.Bids {
 		if len(bids) != 2 {
-			return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp)
+			return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp))
 		}
 	}
 
-	return exchange.OrderBook{Ask: temp.Asks, Bid: temp.Bids}, nil
+	// gateio has a single book id, it serves as both sequence and update id.
+	return exchange.OrderBook{
+		Ask:       temp.Asks,
+		Bid:       temp.Bids,
+		Timestamp: temp.Current,
+		Sequence:  temp.Id,
+		UpdateID:  temp.Id,
+	}, nil
 }
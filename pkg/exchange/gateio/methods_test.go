@@ -0,0 +1,134 @@
+package gateio
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     []exchange.Pair
+		wantErr  string
+		wantKind error
+	}{
+		{
+			name: "get_pairs_ok",
+			want: []exchange.Pair{
+				{Id: "BTC_USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26752.4"), Bid: exchangetest.Dec("26752.3"), Volume: exchangetest.Dec("987.123")},
+				{Id: "ETH_USDT", BaseAsset: "ETH", QuoteAsset: "USDT", Ask: exchangetest.Dec("1630.6"), Bid: exchangetest.Dec("1630.55"), Volume: exchangetest.Dec("15001.5")},
+			},
+		},
+		{
+			name: "get_pairs_retry_ok",
+			want: []exchange.Pair{
+				{Id: "BTC_USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26752.4"), Bid: exchangetest.Dec("26752.3"), Volume: exchangetest.Dec("987.123")},
+				{Id: "ETH_USDT", BaseAsset: "ETH", QuoteAsset: "USDT", Ask: exchangetest.Dec("1630.6"), Bid: exchangetest.Dec("1630.55"), Volume: exchangetest.Dec("15001.5")},
+			},
+		},
+		{
+			name:     "get_pairs_label_error",
+			wantErr:  "400 [INVALID_PARAM_VALUE]",
+			wantKind: exchange.ErrInvalidArgument,
+		},
+		{
+			name:     "get_pairs_http_error",
+			wantErr:  "/spot/currency_pairs 502",
+			wantKind: exchange.ErrUpstreamUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAPI(exchangetest.Cassette(t, tt.name)...).GetPairs(context.Background())
+			exchangetest.CheckErr(t, err, tt.wantErr, tt.wantKind, exchangeID)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     exchange.OrderBook
+		wantErr  string
+		wantKind error
+	}{
+		{
+			name: "get_order_book_ok",
+			want: exchange.OrderBook{
+				Ask: [][]decimal.Decimal{{exchangetest.Dec("26752.4"), exchangetest.Dec("0.25")}, {exchangetest.Dec("26752.5"), exchangetest.Dec("1")}},
+				Bid: [][]decimal.Decimal{{exchangetest.Dec("26752.3"), exchangetest.Dec("0.4")}, {exchangetest.Dec("26752.1"), exchangetest.Dec("3")}},
+
+				Timestamp: 1695200000123,
+				Sequence:  987654321,
+				UpdateID:  987654321,
+			},
+		},
+		{
+			name:     "get_order_book_label_error",
+			wantErr:  "400 [INVALID_CURRENCY_PAIR]",
+			wantKind: exchange.ErrPairNotFound,
+		},
+		{
+			name:     "get_order_book_parse_error",
+			wantErr:  "json parse error",
+			wantKind: exchange.ErrBadResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAPI(exchangetest.Cassette(t, tt.name)...).GetOrderBook(context.Background(), "BTC_USDT")
+			exchangetest.CheckErr(t, err, tt.wantErr, tt.wantKind, exchangeID)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOrderBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPairsVolume(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/spot/currency_pairs":
+			_, _ = w.Write([]byte(`[{"id":"BTC_USDT","base":"BTC","quote":"USDT","trade_status":"tradable"},{"id":"ETH_USDT","base":"ETH","quote":"USDT","trade_status":"tradable"}]`))
+		case "/spot/tickers":
+			_, _ = w.Write([]byte(`[{"currency_pair":"BTC_USDT","lowest_ask":"26752.4","highest_bid":"26752.3"},{"currency_pair":"ETH_USDT","lowest_ask":"1630.6","highest_bid":"1630.55","base_volume":"n/a"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	got, err := NewAPI(exchange.WithBaseURL(srv.URL), exchange.WithRateLimit(0, 1)).GetPairs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []exchange.Pair{
+		{Id: "BTC_USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26752.4"), Bid: exchangetest.Dec("26752.3"), Volume: decimal.Zero},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPairs() = %v, want %v", got, want)
+	}
+}
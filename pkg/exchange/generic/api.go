@@ -0,0 +1,75 @@
+package generic
+
+import (
+	"exchanges/pkg/breaker"
+	"exchanges/pkg/cache"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/logger"
+	"exchanges/pkg/ratelimit"
+	"net/http"
+)
+
+func init() {
+	exchange.RegisterOptIn(adapterID, func(opts ...exchange.Option) (exchange.Exchange, error) {
+		return NewAPI(opts...)
+	})
+}
+
+func NewAPI(opts ...exchange.Option) (*API, error) {
+	cfg := exchange.NewOptions(exchange.Options{
+		ID:        adapterID,
+		RateLimit: rateLimit,
+		RateBurst: rateBurst,
+		PairsCache: exchange.CacheTimeout{
+			Timeout:      pairsCacheTimeout,
+			StaleTimeout: pairsCacheStaleTimeout,
+		},
+		TickersCache: exchange.CacheTimeout{
+			Timeout:      tickersCacheTimeout,
+			StaleTimeout: tickersCacheStaleTimeout,
+		},
+	}, opts...)
+
+	spec, err := ParseSpec(cfg.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := spec.BaseURL
+
+	if len(cfg.BaseURL) > 0 {
+		baseURL = cfg.BaseURL
+	}
+
+	return &API{
+		id:           cfg.ID,
+		spec:         spec,
+		cli:          cfg.HTTPClient(),
+		db:           cache.NewDB(cfg.ID),
+		limiter:      ratelimit.NewLimiter(cfg.RateLimit, cfg.RateBurst),
+		breakers:     breaker.NewSet(cfg.Breaker),
+		retry:        cfg.Retry,
+		health:       exchange.NewHealthTracker(),
+		baseURL:      baseURL,
+		userAgent:    cfg.UserAgent,
+		pairsCache:   cfg.PairsCache,
+		tickersCache: cfg.TickersCache,
+		wireLog:      logger.NewWireLog(cfg.ID, cfg.Debug, cfg.WireLogBody),
+	}, nil
+}
+
+type API struct {
+	id           string
+	spec         *Spec
+	cli          *http.Client
+	db           *cache.DB
+	limiter      *ratelimit.Limiter
+	breakers     *breaker.Set
+	retry        exchange.RetryPolicy
+	health       *exchange.HealthTracker
+	baseURL      string
+	userAgent    string
+	pairsCache   exchange.CacheTimeout
+	tickersCache exchange.CacheTimeout
+	wireLog      *logger.WireLog
+}
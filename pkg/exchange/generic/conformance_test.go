@@ -0,0 +1,73 @@
+package generic
+
+import (
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// conformanceSpec reads prices from the pairs endpoint and reports errors through the HTTP status.
+const conformanceSpec = `{
+  "base_url": "http://localhost",
+  "pairs": {
+    "path": "/api/markets",
+    "items": "$.data",
+    "fields": {"id": "$.name", "base": "$.base", "quote": "$.quote", "status": "$.state", "ask": "$.best.ask", "bid": "$.best.bid", "volume": "$.volume"},
+    "status": ["open"]
+  },
+  "order_book": {
+    "path": "/api/markets/{pair}/book",
+    "asks": "$.data.asks",
+    "bids": "$.data.bids",
+    "price": "$.p",
+    "amount": "$.q"
+  },
+  "errors": {
+    "code": "$.error.code",
+    "message": "$.error.message",
+    "rules": [{"code": "NO_MARKET", "kind": "pair_not_found"}]
+  }
+}`
+
+func TestConformance(t *testing.T) {
+	exchangetest.Run(t, exchangetest.Suite{
+		New: func(t *testing.T, baseURL string) exchange.Exchange {
+			obj, err := NewAPI(
+				exchange.WithID("demo"),
+				exchange.WithParams([]byte(conformanceSpec)),
+				exchange.WithBaseURL(baseURL),
+				exchange.WithRateLimit(0, 1),
+				exchange.WithRetry(exchange.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return obj
+		},
+		Routes: []exchangetest.Route{
+			{
+				Path: "/api/markets",
+				Body: `{"data":[{"name":"BTC_USDT","base":"BTC","quote":"USDT","state":"open","best":{"ask":"26750.01","bid":"26750"},"volume":"1520.318"},{"name":"ETH_USDT","base":"ETH","quote":"USDT","state":"open","best":{"ask":1630.5,"bid":1630.4}},{"name":"NEW_USDT","base":"NEW","quote":"USDT","state":"soon","best":{"ask":"1","bid":"0.9"}},{"name":"BAD_USDT","base":"BAD","quote":"USDT","state":"open","best":{"ask":"1","bid":"0"}}]}`,
+			},
+			{
+				Path: "/api/markets/BTC_USDT/book",
+				Body: `{"data":{"asks":[{"p":"26750.01","q":"1.2"},{"p":"26750.5","q":"0.3"}],"bids":[{"p":"26750","q":"0.8"},{"p":"26749.99","q":"2.5"}]}}`,
+			},
+			{
+				Path:   "/api/markets/NOPE_USDT/book",
+				Status: http.StatusNotFound,
+				Body:   `{"error":{"code":"NO_MARKET","message":"market not found"}}`,
+			},
+			{
+				Path: "/api/markets/BAD_USDT/book",
+				Body: `{"data":{"asks":[{"p":"1.01","q":"5"}],"bids":[{"p":"1"}]}}`,
+			},
+		},
+		Pair:          "BTC_USDT",
+		UnknownPair:   "NOPE_USDT",
+		MalformedPair: "BAD_USDT",
+	})
+}
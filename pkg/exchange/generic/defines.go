@@ -0,0 +1,19 @@
+package generic
+
+import (
+	"time"
+)
+
+const (
+	adapterID = "generic"
+
+	rateLimit = 1
+	rateBurst = 1
+
+	pairPlaceholder = "{pair}"
+
+	pairsCacheTimeout        = time.Minute * 5
+	pairsCacheStaleTimeout   = time.Hour
+	tickersCacheTimeout      = time.Second * 5
+	tickersCacheStaleTimeout = time.Second * 30
+)
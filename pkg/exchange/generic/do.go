@@ -0,0 +1,155 @@
+package generic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/metrics"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func (a *API) doPublicGET(ctx context.Context, endpoint Endpoint, pairID string, result *any) error {
+	path := strings.ReplaceAll(endpoint.Path, pairPlaceholder, url.PathEscape(pairID))
+	brk := a.breakers.Get(endpoint.Path)
+
+	if err := brk.Allow(); err != nil {
+		return exchange.WrapError(exchange.ErrCircuitOpen, a.id, fmt.Errorf("%s: %w", endpoint.Path, err))
+	}
+
+	payload := url.Values{}
+
+	for key, value := range endpoint.Query {
+		payload.Set(key, strings.ReplaceAll(value, pairPlaceholder, pairID))
+	}
+
+	return exchange.RetryGuarded(ctx, a.retry, brk, a.health, func() error {
+		return a.get(ctx, endpoint.Path, path, payload, result)
+	})
+}
+
+// get takes the endpoint template for metrics labels and the expanded path for the request.
+func (a *API) get(ctx context.Context, endpoint, path string, payload url.Values, result *any) error {
+	start := time.Now()
+
+	if err := a.limiter.Wait(ctx); err != nil {
+		return exchange.WrapError(exchange.TransportKind(err), a.id, err)
+	}
+
+	metrics.ObserveRateLimitWait(a.id, time.Since(start))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.URL.RawQuery = payload.Encode()
+
+	req.Header.Add("Accept", "application/json")
+
+	if len(a.userAgent) > 0 {
+		req.Header.Set("User-Agent", a.userAgent)
+	}
+
+	start = time.Now()
+	err = a.do(req, result)
+
+	metrics.ObserveUpstream(a.id, endpoint, exchange.ResultCode(err), time.Since(start))
+
+	return err
+}
+
+func (a *API) do(req *http.Request, result *any) error {
+	start := time.Now()
+
+	rsp, err := a.cli.Do(req)
+	if err != nil {
+		return exchange.WrapError(exchange.TransportKind(err), a.id, err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return exchange.WrapError(exchange.TransportKind(err), a.id, err)
+	}
+
+	a.wireLog.Log(req.Context(), req, rsp, body, time.Since(start))
+
+	var value any
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	parseErr := dec.Decode(&value)
+	rules := a.spec.Errors
+
+	if parseErr == nil && !rules.success(value) || rsp.StatusCode != 200 {
+		var code, message string
+
+		if parseErr == nil {
+			code, message = rules.Code.Text(value), rules.Message.Text(value)
+		}
+
+		text := fmt.Sprintf("%s %s %d", req.Method, req.URL, rsp.StatusCode)
+
+		if len(code) > 0 || len(message) > 0 {
+			text += fmt.Sprintf(" [%s: %s]", code, message)
+		}
+
+		return exchange.NewError(rules.kind(code, message, rsp.StatusCode), a.id, code, text).WithRetryAfter(exchange.RetryAfter(rsp))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if parseErr != nil {
+		return exchange.WrapError(exchange.ErrBadResponse, a.id, parseErr)
+	}
+
+	*result = value
+
+	return nil
+}
+
+func (e ErrorsSpec) success(value any) bool {
+	for _, row := range e.Success {
+		if row.Path.Text(value) != row.Equals {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e ErrorsSpec) kind(code, message string, statusCode int) error {
+	for _, row := range e.Rules {
+		if len(row.Code) > 0 && row.Code != code {
+			continue
+		}
+
+		if len(row.MessageContains) > 0 && !strings.Contains(strings.ToLower(message), strings.ToLower(row.MessageContains)) {
+			continue
+		}
+
+		return row.kind
+	}
+
+	if statusCode != 200 {
+		if kind, ok := e.statusKinds[statusCode]; ok {
+			return kind
+		}
+
+		return exchange.StatusKind(statusCode)
+	}
+
+	return exchange.ErrBadResponse
+}
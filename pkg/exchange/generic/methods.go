@@ -0,0 +1,308 @@
+package generic
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"slices"
+	"strconv"
+)
+
+func (a *API) GetID() string {
+	return a.id
+}
+
+func (a *API) FlushCache() {
+	a.db.Flush()
+}
+
+func (a *API) Health() exchange.Health {
+	result := a.health.Health()
+	result.SetBreakers(a.breakers.States())
+
+	return result
+}
+
+func (a *API) SetWireLog(enabled bool) {
+	a.wireLog.SetEnabled(enabled)
+}
+
+func (a *API) Ping(ctx context.Context) error {
+	if a.spec.Ping != nil {
+		return a.doPublicGET(ctx, *a.spec.Ping, "", nil)
+	}
+
+	return a.doPublicGET(ctx, a.spec.Pairs.Endpoint, "", nil)
+}
+
+func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
+	pairs, pairsStale, err := a.getPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.spec.Tickers == nil {
+		return pairs, nil
+	}
+
+	tickers, tickersStale, err := a.getTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []exchange.Pair
+
+	for _, row := range pairs {
+		if askBid, ok := tickers[row.Id]; ok {
+			row.Ask, row.Bid, row.Volume = askBid[0], askBid[1], askBid[2]
+			row.Stale = pairsStale || tickersStale
+
+			result = append(result, row)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, bool, error) {
+	cacheKey := "getPairs"
+
+	timeout, staleTimeout := a.pairsCache.Timeout, a.pairsCache.StaleTimeout
+
+	// Prices come with the pairs, so they expire like tickers.
+	if a.spec.Tickers == nil {
+		timeout, staleTimeout = a.tickersCache.Timeout, a.tickersCache.StaleTimeout
+	}
+
+	data, stale, err := a.db.Load(ctx, cacheKey, timeout, staleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchPairs(ctx)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	pairs := data.([]exchange.Pair)
+
+	if stale {
+		pairs = slices.Clone(pairs)
+
+		for i := range pairs {
+			pairs[i].Stale = true
+		}
+	}
+
+	return pairs, stale, nil
+}
+
+func (a *API) fetchPairs(ctx context.Context) ([]exchange.Pair, error) {
+	spec := a.spec.Pairs
+
+	var temp any
+
+	if err := a.doPublicGET(ctx, spec.Endpoint, "", &temp); err != nil {
+		return nil, err
+	}
+
+	items, err := a.items(spec.Items, temp)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []exchange.Pair
+
+	for _, row := range items {
+		if len(spec.Status) > 0 && !slices.Contains(spec.Status, spec.Fields.Status.Text(row)) {
+			continue
+		}
+
+		pair := exchange.Pair{
+			Id:         spec.Fields.Id.Text(row),
+			BaseAsset:  spec.Fields.Base.Text(row),
+			QuoteAsset: spec.Fields.Quote.Text(row),
+		}
+
+		if len(pair.Id) == 0 {
+			continue
+		}
+
+		if len(pair.BaseAsset) == 0 {
+			continue
+		}
+
+		if len(pair.QuoteAsset) == 0 {
+			continue
+		}
+
+		if a.spec.Tickers == nil {
+			askBid, ok := prices(row, spec.Fields.Ask, spec.Fields.Bid, spec.Fields.Volume)
+			if !ok {
+				continue
+			}
+
+			pair.Ask, pair.Bid, pair.Volume = askBid[0], askBid[1], askBid[2]
+		}
+
+		result = append(result, pair)
+	}
+
+	return result, nil
+}
+
+func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, bool, error) {
+	cacheKey := "getTickers"
+
+	data, stale, err := a.db.Load(ctx, cacheKey, a.tickersCache.Timeout, a.tickersCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchTickers(ctx)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data.(map[string][]decimal.Decimal), stale, nil
+}
+
+func (a *API) fetchTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
+	spec := a.spec.Tickers
+
+	var temp any
+
+	if err := a.doPublicGET(ctx, spec.Endpoint, "", &temp); err != nil {
+		return nil, err
+	}
+
+	items, err := a.items(spec.Items, temp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]decimal.Decimal)
+
+	for _, row := range items {
+		pairID := spec.Fields.Id.Text(row)
+
+		if len(pairID) == 0 {
+			continue
+		}
+
+		if askBid, ok := prices(row, spec.Fields.Ask, spec.Fields.Bid, spec.Fields.Volume); ok {
+			result[pairID] = askBid
+		}
+	}
+
+	return result, nil
+}
+
+func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBook, error) {
+	if len(pairID) == 0 {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrInvalidArgument, a.id, "", "empty pair id")
+	}
+
+	spec := a.spec.OrderBook
+
+	var temp any
+
+	if err := a.doPublicGET(ctx, spec.Endpoint, pairID, &temp); err != nil {
+		return exchange.OrderBook{}, err
+	}
+
+	asks, err := a.levels(spec.Asks, temp)
+	if err != nil {
+		return exchange.OrderBook{}, err
+	}
+
+	bids, err := a.levels(spec.Bids, temp)
+	if err != nil {
+		return exchange.OrderBook{}, err
+	}
+
+	result := exchange.OrderBook{Ask: asks, Bid: bids}
+
+	for _, row := range []struct {
+		path  Path
+		value *int64
+	}{
+		{spec.Timestamp, &result.Timestamp},
+		{spec.Sequence, &result.Sequence},
+		{spec.UpdateID, &result.UpdateID},
+	} {
+		if text := row.path.Text(temp); len(text) > 0 {
+			if *row.value, err = strconv.ParseInt(text, 10, 64); err != nil {
+				return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, a.id, "", fmt.Sprintf("json parse error: %q is not an integer", row.path))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (a *API) items(path Path, value any) ([]any, error) {
+	found, ok := path.Lookup(value)
+	if !ok || found == nil {
+		return nil, nil
+	}
+
+	items, ok := found.([]any)
+	if !ok {
+		return nil, exchange.NewError(exchange.ErrBadResponse, a.id, "", fmt.Sprintf("json parse error: %q is not a list", path))
+	}
+
+	return items, nil
+}
+
+func (a *API) levels(path Path, value any) ([][]decimal.Decimal, error) {
+	spec := a.spec.OrderBook
+
+	items, err := a.items(path, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result [][]decimal.Decimal
+
+	for _, row := range items {
+		if list, ok := row.([]any); spec.LevelLen > 0 && (!ok || len(list) != spec.LevelLen) {
+			return nil, exchange.NewError(exchange.ErrBadResponse, a.id, "", fmt.Sprintf("json parse error: %q level %v", path, row))
+		}
+
+		price, okPrice := decimalAt(spec.Price, row)
+		amount, okAmount := decimalAt(spec.Amount, row)
+
+		if !okPrice || !okAmount {
+			return nil, exchange.NewError(exchange.ErrBadResponse, a.id, "", fmt.Sprintf("json parse error: %q level %v", path, row))
+		}
+
+		result = append(result, []decimal.Decimal{price, amount})
+	}
+
+	return result, nil
+}
+
+func prices(row any, ask, bid, volume Path) ([]decimal.Decimal, bool) {
+	askValue, ok := decimalAt(ask, row)
+	if !ok || askValue.LessThanOrEqual(decimal.Zero) {
+		return nil, false
+	}
+
+	bidValue, ok := decimalAt(bid, row)
+	if !ok || bidValue.LessThanOrEqual(decimal.Zero) {
+		return nil, false
+	}
+
+	volumeValue, _ := decimalAt(volume, row)
+
+	return []decimal.Decimal{askValue, bidValue, volumeValue}, true
+}
+
+func decimalAt(path Path, value any) (decimal.Decimal, bool) {
+	if path.IsZero() {
+		return decimal.Zero, false
+	}
+
+	result, err := decimal.NewFromString(path.Text(value))
+	if err != nil {
+		return decimal.Zero, false
+	}
+
+	return result, true
+}
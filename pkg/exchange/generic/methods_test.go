@@ -0,0 +1,126 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/bybit"
+	"exchanges/pkg/exchange/exchangetest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func bybitCassette(t *testing.T, name string) []exchange.Option {
+	t.Helper()
+
+	return exchangetest.Replay(t, filepath.Join("..", "bybit", "testdata", name+".json"))
+}
+
+// bybitSpec returns the spec that re-expresses the bybit adapter; config.example.json carries the same spec as bybit_spec.
+func bybitSpec(t *testing.T) json.RawMessage {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "bybit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func newBybitSpec(t *testing.T, name string) *API {
+	t.Helper()
+
+	obj, err := NewAPI(append(bybitCassette(t, name), exchange.WithID("bybit"), exchange.WithParams(bybitSpec(t)))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return obj
+}
+
+func checkParity(t *testing.T, got, want any, gotErr, wantErr error) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("result = %v, want %v", got, want)
+	}
+
+	if (gotErr == nil) != (wantErr == nil) {
+		t.Fatalf("error = %v, want %v", gotErr, wantErr)
+	}
+
+	if wantErr == nil {
+		return
+	}
+
+	if exchange.ErrorCode(gotErr) != exchange.ErrorCode(wantErr) {
+		t.Errorf("error code = %q, want %q", exchange.ErrorCode(gotErr), exchange.ErrorCode(wantErr))
+	}
+
+	var gotE, wantE *exchange.Error
+
+	if !errors.As(gotErr, &gotE) || !errors.As(wantErr, &wantE) {
+		t.Fatalf("error = %#v, want *exchange.Error", gotErr)
+	}
+
+	if gotE.Exchange != wantE.Exchange {
+		t.Errorf("exchange = %q, want %q", gotE.Exchange, wantE.Exchange)
+	}
+}
+
+// TestBybitParity replays the bybit cassettes through the hand-written adapter and the bybit spec.
+func TestBybitParity(t *testing.T) {
+	for _, name := range []string{"get_pairs_ok", "get_pairs_retry_ok", "get_pairs_retcode_error", "get_pairs_http_error"} {
+		name := name
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			want, wantErr := bybit.NewAPI(bybitCassette(t, name)...).GetPairs(context.Background())
+			got, gotErr := newBybitSpec(t, name).GetPairs(context.Background())
+
+			checkParity(t, got, want, gotErr, wantErr)
+		})
+	}
+
+	for _, name := range []string{"get_order_book_ok", "get_order_book_retcode_error", "get_order_book_parse_error"} {
+		name := name
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			want, wantErr := bybit.NewAPI(bybitCassette(t, name)...).GetOrderBook(context.Background(), "BTCUSDT")
+			got, gotErr := newBybitSpec(t, name).GetOrderBook(context.Background(), "BTCUSDT")
+
+			checkParity(t, got, want, gotErr, wantErr)
+		})
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "empty", spec: "", wantErr: true},
+		{name: "missing_order_book", spec: `{"base_url":"http://x","pairs":{"path":"/p","items":"$","fields":{"id":"$.s","base":"$.b","quote":"$.q","ask":"$.a","bid":"$.c"}}}`, wantErr: true},
+		{name: "bad_path", spec: `{"base_url":"http://x","pairs":{"path":"/p","items":"$.list[x]"}}`, wantErr: true},
+		{name: "unknown_kind", spec: `{"base_url":"http://x","pairs":{"path":"/p","items":"$","fields":{"id":"$.s","base":"$.b","quote":"$.q","ask":"$.a","bid":"$.c"}},"order_book":{"path":"/b","asks":"$.a","bids":"$.b"},"errors":{"rules":[{"kind":"nope"}]}}`, wantErr: true},
+		{name: "ok", spec: `{"base_url":"http://x","pairs":{"path":"/p","items":"$","fields":{"id":"$.s","base":"$.b","quote":"$.q","ask":"$.a","bid":"$.c"}},"order_book":{"path":"/b","asks":"$.a","bids":"$.b"}}`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSpec([]byte(tt.spec)); (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
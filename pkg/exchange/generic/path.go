@@ -0,0 +1,143 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a small JSONPath subset: `$`, `.key` and `[index]`, e.g. `$.result.list[0].symbol`.
+type Path struct {
+	raw  string
+	segs []segment
+}
+
+type segment struct {
+	key   string
+	index int
+}
+
+func ParsePath(raw string) (Path, error) {
+	p := Path{raw: raw}
+	s := strings.TrimPrefix(raw, "$")
+
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+
+			if end == 0 {
+				return p, fmt.Errorf("path %q: empty key", raw)
+			}
+
+			p.segs = append(p.segs, segment{key: s[:end], index: -1})
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return p, fmt.Errorf("path %q: unterminated index", raw)
+			}
+
+			index, err := strconv.Atoi(s[1:end])
+			if err != nil || index < 0 {
+				return p, fmt.Errorf("path %q: bad index %q", raw, s[1:end])
+			}
+
+			p.segs = append(p.segs, segment{index: index})
+			s = s[end+1:]
+		default:
+			if len(p.segs) > 0 || len(s) < len(raw) {
+				return p, fmt.Errorf("path %q: unexpected %q", raw, s[0])
+			}
+
+			s = "." + s
+		}
+	}
+
+	return p, nil
+}
+
+func (p Path) String() string {
+	return p.raw
+}
+
+func (p Path) IsZero() bool {
+	return len(p.raw) == 0
+}
+
+func (p *Path) UnmarshalJSON(data []byte) error {
+	var raw string
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := ParsePath(raw)
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
+
+// Lookup finds the value at the path, an unset path finds nothing; use `$` for the root.
+func (p Path) Lookup(value any) (any, bool) {
+	if p.IsZero() {
+		return nil, false
+	}
+
+	for _, seg := range p.segs {
+		if seg.index < 0 {
+			obj, ok := value.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+
+			if value, ok = obj[seg.key]; !ok {
+				return nil, false
+			}
+
+			continue
+		}
+
+		list, ok := value.([]any)
+		if !ok || seg.index >= len(list) {
+			return nil, false
+		}
+
+		value = list[seg.index]
+	}
+
+	return value, true
+}
+
+// Text returns scalars as text, so that `"0"` and `0` compare equal in envelope rules.
+func (p Path) Text(value any) string {
+	value, ok := p.Lookup(value)
+	if !ok {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+
+	data, _ := json.Marshal(value)
+
+	return string(data)
+}
@@ -0,0 +1,173 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"exchanges/pkg/exchange"
+	"fmt"
+	"strconv"
+)
+
+type Spec struct {
+	BaseURL   string        `json:"base_url"`
+	Ping      *Endpoint     `json:"ping,omitempty"`
+	Pairs     PairsSpec     `json:"pairs"`
+	Tickers   *TickersSpec  `json:"tickers,omitempty"`
+	OrderBook OrderBookSpec `json:"order_book"`
+	Errors    ErrorsSpec    `json:"errors"`
+}
+
+// Endpoint is a GET request; `{pair}` in the path or a query value is replaced with the pair id.
+type Endpoint struct {
+	Path  string            `json:"path"`
+	Query map[string]string `json:"query,omitempty"`
+}
+
+type PairsSpec struct {
+	Endpoint
+	Items  Path       `json:"items"`
+	Fields PairFields `json:"fields"`
+	// Status keeps only items whose status field equals one of the values.
+	Status []string `json:"status,omitempty"`
+}
+
+type PairFields struct {
+	Id     Path `json:"id"`
+	Base   Path `json:"base"`
+	Quote  Path `json:"quote"`
+	Status Path `json:"status,omitempty"`
+	Ask    Path `json:"ask,omitempty"`
+	Bid    Path `json:"bid,omitempty"`
+	Volume Path `json:"volume,omitempty"`
+}
+
+type TickersSpec struct {
+	Endpoint
+	Items  Path         `json:"items"`
+	Fields TickerFields `json:"fields"`
+}
+
+type TickerFields struct {
+	Id     Path `json:"id"`
+	Ask    Path `json:"ask"`
+	Bid    Path `json:"bid"`
+	Volume Path `json:"volume,omitempty"`
+}
+
+type OrderBookSpec struct {
+	Endpoint
+	Asks   Path `json:"asks"`
+	Bids   Path `json:"bids"`
+	Price  Path `json:"price,omitempty"`
+	Amount Path `json:"amount,omitempty"`
+	// Timestamp is read as unix milliseconds, Sequence and UpdateID as integers.
+	Timestamp Path `json:"timestamp,omitempty"`
+	Sequence  Path `json:"sequence,omitempty"`
+	UpdateID  Path `json:"update_id,omitempty"`
+	// LevelLen is the exact number of elements of a level, any length is accepted when 0.
+	LevelLen int `json:"level_len,omitempty"`
+}
+
+// ErrorsSpec describes the response envelope. A JSON response that fails any success
+// condition, or a non-200 response, is an error classified by the first matching rule.
+type ErrorsSpec struct {
+	Code        Path              `json:"code,omitempty"`
+	Message     Path              `json:"message,omitempty"`
+	Success     []Condition       `json:"success,omitempty"`
+	Rules       []Rule            `json:"rules,omitempty"`
+	StatusKinds map[string]string `json:"status_kinds,omitempty"`
+
+	statusKinds map[int]error
+}
+
+type Condition struct {
+	Path   Path   `json:"path"`
+	Equals string `json:"equals"`
+}
+
+type Rule struct {
+	Code            string `json:"code,omitempty"`
+	MessageContains string `json:"message_contains,omitempty"`
+	Kind            string `json:"kind"`
+
+	kind error
+}
+
+func ParseSpec(raw json.RawMessage) (*Spec, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("generic params: spec is required")
+	}
+
+	var s Spec
+
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("generic params: %w", err)
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("generic params: %w", err)
+	}
+
+	return &s, nil
+}
+
+func (s *Spec) validate() error {
+	if len(s.BaseURL) == 0 {
+		return errors.New("base_url is required")
+	}
+
+	if len(s.Pairs.Path) == 0 || s.Pairs.Items.IsZero() || s.Pairs.Fields.Id.IsZero() || s.Pairs.Fields.Base.IsZero() || s.Pairs.Fields.Quote.IsZero() {
+		return errors.New("pairs: path, items, fields.id, fields.base and fields.quote are required")
+	}
+
+	if len(s.Pairs.Status) > 0 && s.Pairs.Fields.Status.IsZero() {
+		return errors.New("pairs: status requires fields.status")
+	}
+
+	if s.Tickers != nil {
+		if len(s.Tickers.Path) == 0 || s.Tickers.Items.IsZero() || s.Tickers.Fields.Id.IsZero() || s.Tickers.Fields.Ask.IsZero() || s.Tickers.Fields.Bid.IsZero() {
+			return errors.New("tickers: path, items, fields.id, fields.ask and fields.bid are required")
+		}
+	} else if s.Pairs.Fields.Ask.IsZero() || s.Pairs.Fields.Bid.IsZero() {
+		return errors.New("pairs: fields.ask and fields.bid are required without tickers")
+	}
+
+	if len(s.OrderBook.Path) == 0 || s.OrderBook.Asks.IsZero() || s.OrderBook.Bids.IsZero() {
+		return errors.New("order_book: path, asks and bids are required")
+	}
+
+	if s.OrderBook.Price.IsZero() {
+		s.OrderBook.Price, _ = ParsePath("[0]")
+	}
+
+	if s.OrderBook.Amount.IsZero() {
+		s.OrderBook.Amount, _ = ParsePath("[1]")
+	}
+
+	for i := range s.Errors.Rules {
+		kind, ok := exchange.KindByCode(s.Errors.Rules[i].Kind)
+		if !ok {
+			return fmt.Errorf("errors.rules[%d]: unknown kind %q", i, s.Errors.Rules[i].Kind)
+		}
+
+		s.Errors.Rules[i].kind = kind
+	}
+
+	s.Errors.statusKinds = make(map[int]error)
+
+	for status, code := range s.Errors.StatusKinds {
+		statusCode, err := strconv.Atoi(status)
+		if err != nil {
+			return fmt.Errorf("errors.status_kinds: bad status %q", status)
+		}
+
+		kind, ok := exchange.KindByCode(code)
+		if !ok {
+			return fmt.Errorf("errors.status_kinds: unknown kind %q", code)
+		}
+
+		s.Errors.statusKinds[statusCode] = kind
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package exchange
+
+import (
+	"context"
+	"exchanges/pkg/breaker"
+	"sync"
+	"time"
+)
+
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+	HealthUnknown  = "unknown"
+)
+
+type Health struct {
+	Status        string            `json:"status"`
+	LastSuccess   *time.Time        `json:"last_success,omitempty"`
+	LastError     string            `json:"last_error,omitempty"`
+	LastErrorTime *time.Time        `json:"last_error_time,omitempty"`
+	Failures      int               `json:"consecutive_failures"`
+	Breakers      map[string]string `json:"breakers,omitempty"`
+}
+
+type HealthReporter interface {
+	Health() Health
+}
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+func NewHealthTracker() *HealthTracker {
+	return &HealthTracker{
+		mu: new(sync.Mutex),
+	}
+}
+
+type HealthTracker struct {
+	mu          *sync.Mutex
+	lastSuccess time.Time
+	lastError   time.Time
+	err         error
+	failures    int
+}
+
+func (t *HealthTracker) Observe(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	if !IsFailure(err) {
+		t.lastSuccess = now
+		t.failures = 0
+		return
+	}
+
+	t.lastError = now
+	t.err = err
+	t.failures++
+}
+
+func (t *HealthTracker) Health() Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result Health
+
+	if !t.lastSuccess.IsZero() {
+		lastSuccess := t.lastSuccess
+		result.LastSuccess = &lastSuccess
+	}
+
+	if !t.lastError.IsZero() {
+		lastError := t.lastError
+		result.LastError = t.err.Error()
+		result.LastErrorTime = &lastError
+	}
+
+	result.Failures = t.failures
+
+	switch {
+	case t.lastSuccess.IsZero() && t.lastError.IsZero():
+		result.Status = HealthUnknown
+	case t.failures >= healthDownFailures:
+		result.Status = HealthDown
+	case t.failures > 0:
+		result.Status = HealthDegraded
+	case time.Since(t.lastError) < healthDegradedWindow:
+		result.Status = HealthDegraded
+	default:
+		result.Status = HealthHealthy
+	}
+
+	return result
+}
+
+func (h *Health) SetBreakers(states map[string]string) {
+	h.Breakers = states
+
+	for _, state := range states {
+		if state == breaker.StateOpen {
+			h.Status = HealthDown
+		}
+	}
+}
+
+func (h Health) LastActivity() time.Time {
+	var result time.Time
+
+	if h.LastSuccess != nil {
+		result = *h.LastSuccess
+	}
+
+	if h.LastErrorTime != nil && h.LastErrorTime.After(result) {
+		result = *h.LastErrorTime
+	}
+
+	return result
+}
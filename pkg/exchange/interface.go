@@ -9,3 +9,15 @@ type Exchange interface {
 	GetPairs(ctx context.Context) ([]Pair, error)
 	GetOrderBook(ctx context.Context, pairID string) (OrderBook, error)
 }
+
+type CacheFlusher interface {
+	FlushCache()
+}
+
+type WireLogger interface {
+	SetWireLog(enabled bool)
+}
+
+type TradeLister interface {
+	GetTrades(ctx context.Context, pairID string) ([]Trade, error)
+}
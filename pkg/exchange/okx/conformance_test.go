@@ -0,0 +1,48 @@
+package okx
+
+import (
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"testing"
+	"time"
+)
+
+func TestConformance(t *testing.T) {
+	exchangetest.Run(t, exchangetest.Suite{
+		New: func(t *testing.T, baseURL string) exchange.Exchange {
+			return NewAPI(
+				exchange.WithBaseURL(baseURL),
+				exchange.WithRateLimit(0, 1),
+				exchange.WithRetry(exchange.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+			)
+		},
+		Routes: []exchangetest.Route{
+			{
+				Path: "/api/v5/public/instruments",
+				Body: `{"code":"0","msg":"","data":[{"instId":"BTC-USDT","baseCcy":"BTC","quoteCcy":"USDT","state":"live"},{"instId":"ETH-USDT","baseCcy":"ETH","quoteCcy":"USDT","state":"live"},{"instId":"OLD-USDT","baseCcy":"OLD","quoteCcy":"USDT","state":"suspend"},{"instId":"","baseCcy":"X","quoteCcy":"USDT","state":"live"}]}`,
+			},
+			{
+				Path: "/api/v5/market/tickers",
+				Body: `{"code":"0","msg":"","data":[{"instId":"BTC-USDT","askPx":"26751.2","bidPx":"26751.1","vol24h":"2210.45"},{"instId":"ETH-USDT","askPx":"1630.52","bidPx":"1630.51","vol24h":"31877.02"},{"instId":"OLD-USDT","askPx":"1","bidPx":"0.9"}]}`,
+			},
+			{
+				Path:  "/api/v5/market/books",
+				Query: map[string]string{"instId": "BTC-USDT"},
+				Body:  `{"code":"0","msg":"","data":[{"asks":[["26751.3","1.1","0","2"],["26751.2","0.5","0","3"]],"bids":[["26750.9","2","0","1"],["26751.1","0.7","0","4"]],"ts":"1695200000000"}]}`,
+			},
+			{
+				Path:  "/api/v5/market/books",
+				Query: map[string]string{"instId": "NOPE-USDT"},
+				Body:  `{"code":"51001","msg":"Instrument ID does not exist","data":[]}`,
+			},
+			{
+				Path:  "/api/v5/market/books",
+				Query: map[string]string{"instId": "BAD-USDT"},
+				Body:  `{"code":"0","msg":"","data":[{"asks":[["1.01","5","0","1"]],"bids":[["1","5"]],"ts":"1695200000000"}]}`,
+			},
+		},
+		Pair:          "BTC-USDT",
+		UnknownPair:   "NOPE-USDT",
+		MalformedPair: "BAD-USDT",
+	})
+}
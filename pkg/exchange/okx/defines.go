@@ -1,12 +1,35 @@
 package okx
 
-import "time"
+import (
+	"exchanges/pkg/exchange"
+	"time"
+)
 
 const (
-	doPause = time.Second
-	baseURL = "https://www.okx.com"
+	exchangeID = "okx"
+
+	baseURL   = "https://www.okx.com"
+	rateLimit = 1
+	rateBurst = 1
+
+	pairsCacheTimeout        = time.Minute * 5
+	pairsCacheStaleTimeout   = time.Hour
+	tickersCacheTimeout      = time.Second * 5
+	tickersCacheStaleTimeout = time.Second * 30
+
+	checksumLevels = 25
 )
 
 var (
-	Debug bool
+	codeErrors = map[string]error{
+		"50001": exchange.ErrUpstreamUnavailable,
+		"50004": exchange.ErrTimeout,
+		"50011": exchange.ErrRateLimited,
+		"50013": exchange.ErrUpstreamUnavailable,
+		"50014": exchange.ErrInvalidArgument,
+		"50026": exchange.ErrUpstreamUnavailable,
+		"50061": exchange.ErrRateLimited,
+		"51000": exchange.ErrInvalidArgument,
+		"51001": exchange.ErrPairNotFound,
+	}
 )
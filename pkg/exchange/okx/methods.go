@@ -5,21 +5,42 @@ import (
 	"exchanges/pkg/exchange"
 	"fmt"
 	"github.com/shopspring/decimal"
+	"hash/crc32"
 	"net/url"
-	"time"
+	"strconv"
+	"strings"
 )
 
 func (a *API) GetID() string {
-	return "okx"
+	return exchangeID
+}
+
+func (a *API) FlushCache() {
+	a.cacheDB.Flush()
+}
+
+func (a *API) Health() exchange.Health {
+	result := a.health.Health()
+	result.SetBreakers(a.breakers.States())
+
+	return result
+}
+
+func (a *API) SetWireLog(enabled bool) {
+	a.wireLog.SetEnabled(enabled)
+}
+
+func (a *API) Ping(ctx context.Context) error {
+	return a.doPublicGET(ctx, "/api/v5/public/time", nil, nil)
 }
 
 func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
-	pairs, err := a.getPairs(ctx)
+	pairs, pairsStale, err := a.getPairs(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tickers, err := a.getTickers(ctx)
+	tickers, tickersStale, err := a.getTickers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +55,8 @@ func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
 				QuoteAsset: row.QuoteAsset,
 				Ask:        askBid[0],
 				Bid:        askBid[1],
+				Volume:     askBid[2],
+				Stale:      pairsStale || tickersStale,
 			})
 		}
 	}
@@ -41,14 +64,20 @@ func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
 	return result, nil
 }
 
-func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, error) {
+func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, bool, error) {
 	cacheKey := "getPairs"
-	cacheTimeout := time.Minute * 5
 
-	if cache, ok := a.cacheDB.Get(cacheKey).([]exchange.Pair); ok {
-		return cache, nil
+	data, stale, err := a.cacheDB.Load(ctx, cacheKey, a.pairsCache.Timeout, a.pairsCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchPairs(ctx)
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
+	return data.([]exchange.Pair), stale, nil
+}
+
+func (a *API) fetchPairs(ctx context.Context) ([]exchange.Pair, error) {
 	endpoint := "/api/v5/public/instruments"
 
 	payload := url.Values{}
@@ -93,12 +122,23 @@ func (a *API) getPairs(ctx context.Context) ([]exchange.Pair, error) {
 		})
 	}
 
-	a.cacheDB.Set(cacheKey, cacheTimeout, result)
-
 	return result, nil
 }
 
-func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
+func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, bool, error) {
+	cacheKey := "getTickers"
+
+	data, stale, err := a.cacheDB.Load(ctx, cacheKey, a.tickersCache.Timeout, a.tickersCache.StaleTimeout, func(ctx context.Context) (any, error) {
+		return a.fetchTickers(ctx)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data.(map[string][]decimal.Decimal), stale, nil
+}
+
+func (a *API) fetchTickers(ctx context.Context) (map[string][]decimal.Decimal, error) {
 	endpoint := "/api/v5/market/tickers"
 
 	payload := url.Values{}
@@ -109,6 +149,7 @@ func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, err
 			InstId string          `json:"instId"`
 			AskPx  decimal.Decimal `json:"askPx"`
 			BidPx  decimal.Decimal `json:"bidPx"`
+			Vol24h decimal.Decimal `json:"vol24h"`
 		} `json:"data"`
 	}
 
@@ -131,23 +172,30 @@ func (a *API) getTickers(ctx context.Context) (map[string][]decimal.Decimal, err
 			continue
 		}
 
-		result[row.InstId] = []decimal.Decimal{row.AskPx, row.BidPx}
+		result[row.InstId] = []decimal.Decimal{row.AskPx, row.BidPx, row.Vol24h}
 	}
 
 	return result, nil
 }
 
 func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBook, error) {
+	if len(pairID) == 0 {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrInvalidArgument, exchangeID, "", "empty pair id")
+	}
+
 	endpoint := "/api/v5/market/books"
 
 	payload := url.Values{}
 	payload.Set("instId", pairID)
 	payload.Set("sz", "100")
 
+	// Levels stay strings, the checksum is computed over the exact text okx sent.
 	var temp struct {
 		Data []struct {
-			Asks [][]decimal.Decimal `json:"asks"`
-			Bids [][]decimal.Decimal `json:"bids"`
+			Asks     [][]string `json:"asks"`
+			Bids     [][]string `json:"bids"`
+			Ts       string     `json:"ts"`
+			Checksum *int32     `json:"checksum"`
 		} `json:"data"`
 	}
 
@@ -156,26 +204,76 @@ func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBo
 	}
 
 	if len(temp.Data) != 1 {
-		return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp.Data)
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp.Data))
 	}
 
-	var asks, bids [][]decimal.Decimal
+	data := temp.Data[0]
 
-	for _, row := range temp.Data[0].Asks {
-		if len(row) != 4 {
-			return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp.Data)
+	asks, err := parseLevels(data.Asks)
+	if err != nil {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp.Data))
+	}
+
+	bids, err := parseLevels(data.Bids)
+	if err != nil {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: %v", temp.Data))
+	}
+
+	if data.Checksum != nil {
+		if sum := checksum(data.Asks, data.Bids); sum != *data.Checksum {
+			return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("%s order book checksum mismatch: got %d, want %d", pairID, sum, *data.Checksum))
 		}
+	}
+
+	result := exchange.OrderBook{Ask: asks, Bid: bids}
 
-		asks = append(asks, []decimal.Decimal{row[0], row[1]})
+	if len(data.Ts) > 0 {
+		if result.Timestamp, err = strconv.ParseInt(data.Ts, 10, 64); err != nil {
+			return exchange.OrderBook{}, exchange.NewError(exchange.ErrBadResponse, exchangeID, "", fmt.Sprintf("json parse error: ts %q", data.Ts))
+		}
 	}
 
-	for _, row := range temp.Data[0].Asks {
+	return result, nil
+}
+
+func parseLevels(rows [][]string) ([][]decimal.Decimal, error) {
+	var result [][]decimal.Decimal
+
+	for _, row := range rows {
 		if len(row) != 4 {
-			return exchange.OrderBook{}, fmt.Errorf("json parse error: %v", temp.Data)
+			return nil, fmt.Errorf("level %v", row)
+		}
+
+		price, err := decimal.NewFromString(row[0])
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err := decimal.NewFromString(row[1])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, []decimal.Decimal{price, amount})
+	}
+
+	return result, nil
+}
+
+// checksum is the okx book checksum: CRC32 of up to 25 levels per side, alternating
+// bid and ask, as price:size pairs joined by ':'.
+func checksum(asks, bids [][]string) int32 {
+	var parts []string
+
+	for i := 0; i < checksumLevels; i++ {
+		if i < len(bids) {
+			parts = append(parts, bids[i][0], bids[i][1])
 		}
 
-		bids = append(bids, []decimal.Decimal{row[0], row[1]})
+		if i < len(asks) {
+			parts = append(parts, asks[i][0], asks[i][1])
+		}
 	}
 
-	return exchange.OrderBook{Ask: asks, Bid: bids}, nil
+	return int32(crc32.ChecksumIEEE([]byte(strings.Join(parts, ":"))))
 }
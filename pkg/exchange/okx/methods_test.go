@@ -0,0 +1,137 @@
+package okx
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+)
+
+func TestGetPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     []exchange.Pair
+		wantErr  string
+		wantKind error
+	}{
+		{
+			name: "get_pairs_ok",
+			want: []exchange.Pair{
+				{Id: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26751.2"), Bid: exchangetest.Dec("26751.1"), Volume: exchangetest.Dec("2210.45")},
+				{Id: "ETH-USDT", BaseAsset: "ETH", QuoteAsset: "USDT", Ask: exchangetest.Dec("1630.52"), Bid: exchangetest.Dec("1630.51"), Volume: exchangetest.Dec("31877.02")},
+			},
+		},
+		{
+			name: "get_pairs_retry_ok",
+			want: []exchange.Pair{
+				{Id: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: exchangetest.Dec("26751.2"), Bid: exchangetest.Dec("26751.1"), Volume: exchangetest.Dec("2210.45")},
+				{Id: "ETH-USDT", BaseAsset: "ETH", QuoteAsset: "USDT", Ask: exchangetest.Dec("1630.52"), Bid: exchangetest.Dec("1630.51"), Volume: exchangetest.Dec("31877.02")},
+			},
+		},
+		{
+			name:     "get_pairs_msg_error",
+			wantErr:  "429 [50011: Too Many Requests]",
+			wantKind: exchange.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAPI(exchangetest.Cassette(t, tt.name)...).GetPairs(context.Background())
+			exchangetest.CheckErr(t, err, tt.wantErr, tt.wantKind, exchangeID)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     exchange.OrderBook
+		wantErr  string
+		wantKind error
+	}{
+		{
+			name: "get_order_book_ok",
+			want: exchange.OrderBook{
+				Ask: [][]decimal.Decimal{{exchangetest.Dec("26751.2"), exchangetest.Dec("0.5")}, {exchangetest.Dec("26751.3"), exchangetest.Dec("1.1")}},
+				Bid: [][]decimal.Decimal{{exchangetest.Dec("26751.1"), exchangetest.Dec("0.7")}, {exchangetest.Dec("26750.9"), exchangetest.Dec("2")}},
+
+				Timestamp: 1695200000000,
+			},
+		},
+		{
+			name:     "get_order_book_checksum_error",
+			wantErr:  "checksum mismatch",
+			wantKind: exchange.ErrBadResponse,
+		},
+		{
+			name:     "get_order_book_msg_error",
+			wantErr:  "[51001: Instrument ID does not exist]",
+			wantKind: exchange.ErrPairNotFound,
+		},
+		{
+			name:     "get_order_book_parse_error",
+			wantErr:  "json parse error",
+			wantKind: exchange.ErrBadResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAPI(exchangetest.Cassette(t, tt.name)...).GetOrderBook(context.Background(), "BTC-USDT")
+			exchangetest.CheckErr(t, err, tt.wantErr, tt.wantKind, exchangeID)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOrderBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		asks [][]string
+		bids [][]string
+		want int32
+	}{
+		{
+			name: "alternating",
+			asks: [][]string{{"26751.2", "0.5", "0", "3"}, {"26751.3", "1.1", "0", "2"}},
+			bids: [][]string{{"26751.1", "0.7", "0", "4"}, {"26750.9", "2", "0", "1"}},
+			want: 805519406,
+		},
+		{
+			name: "text_as_sent",
+			asks: [][]string{{"3366.8", "9", "0", "1"}},
+			bids: [][]string{{"3366.1", "7.0", "0", "1"}},
+			want: -1627862905,
+		},
+		{
+			name: "uneven_sides",
+			asks: [][]string{{"3366.8", "9", "0", "1"}},
+			bids: [][]string{{"3366.1", "7.0", "0", "1"}, {"3366.0", "1.5", "0", "1"}},
+			want: -458128785,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := checksum(tt.asks, tt.bids); got != tt.want {
+			t.Errorf("%s: checksum = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"exchanges/pkg/breaker"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Options struct {
+	ID        string
+	BaseURL   string
+	Client    *http.Client
+	Transport http.RoundTripper
+	Proxy     *url.URL
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+	UserAgent string
+	RateLimit float64
+	RateBurst int
+	Breaker   breaker.Config
+	Retry     RetryPolicy
+
+	PairsCache   CacheTimeout
+	TickersCache CacheTimeout
+	Debug        bool
+	WireLogBody  int
+	Params       json.RawMessage
+}
+
+type CacheTimeout struct {
+	Timeout      time.Duration
+	StaleTimeout time.Duration
+}
+
+type Option func(o *Options)
+
+func NewOptions(defaults Options, opts ...Option) Options {
+	if defaults.Timeout == 0 {
+		defaults.Timeout = defaultTimeout
+	}
+
+	if defaults.WireLogBody == 0 {
+		defaults.WireLogBody = defaultWireLogBody
+	}
+
+	if defaults.Retry == (RetryPolicy{}) {
+		defaults.Retry = RetryPolicy{
+			MaxAttempts: defaultRetryAttempts,
+			BaseDelay:   defaultRetryBaseDelay,
+			MaxDelay:    defaultRetryMaxDelay,
+		}
+	}
+
+	for _, opt := range opts {
+		opt(&defaults)
+	}
+
+	return defaults
+}
+
+func (o Options) Validate() error {
+	if o.Proxy == nil && o.TLSConfig == nil {
+		return nil
+	}
+
+	if o.Client != nil {
+		return errors.New("proxy and tls options cannot be used with a custom http client")
+	}
+
+	if _, ok := o.Transport.(*http.Transport); o.Transport != nil && !ok {
+		return fmt.Errorf("proxy and tls options need an *http.Transport, got %T", o.Transport)
+	}
+
+	return nil
+}
+
+func (o Options) HTTPClient() *http.Client {
+	if o.Client != nil {
+		return o.Client
+	}
+
+	transport := o.Transport
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if obj, ok := transport.(*http.Transport); ok && (o.Transport == nil || o.Proxy != nil || o.TLSConfig != nil) {
+		obj = obj.Clone()
+
+		if o.Proxy != nil {
+			obj.Proxy = http.ProxyURL(o.Proxy)
+		}
+
+		if o.TLSConfig != nil {
+			obj.TLSClientConfig = o.TLSConfig
+		}
+
+		transport = obj
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   o.Timeout,
+	}
+}
+
+func WithID(exchangeID string) Option {
+	return func(o *Options) {
+		o.ID = exchangeID
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(o *Options) {
+		o.BaseURL = baseURL
+	}
+}
+
+func WithHTTPClient(cli *http.Client) Option {
+	return func(o *Options) {
+		o.Client = cli
+	}
+}
+
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *Options) {
+		o.Transport = rt
+	}
+}
+
+func WithProxy(proxy *url.URL) Option {
+	return func(o *Options) {
+		o.Proxy = proxy
+	}
+}
+
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = cfg
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(o *Options) {
+		o.UserAgent = userAgent
+	}
+}
+
+func WithRateLimit(rate float64, burst int) Option {
+	return func(o *Options) {
+		o.RateLimit = rate
+		o.RateBurst = burst
+	}
+}
+
+func WithPairsCache(timeout, staleTimeout time.Duration) Option {
+	return func(o *Options) {
+		o.PairsCache = CacheTimeout{Timeout: timeout, StaleTimeout: staleTimeout}
+	}
+}
+
+func WithTickersCache(timeout, staleTimeout time.Duration) Option {
+	return func(o *Options) {
+		o.TickersCache = CacheTimeout{Timeout: timeout, StaleTimeout: staleTimeout}
+	}
+}
+
+func WithDebug(debug bool) Option {
+	return func(o *Options) {
+		o.Debug = debug
+	}
+}
+
+func WithBreaker(cfg breaker.Config) Option {
+	return func(o *Options) {
+		o.Breaker = cfg
+	}
+}
+
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *Options) {
+		if policy.MaxAttempts > 0 {
+			o.Retry.MaxAttempts = policy.MaxAttempts
+		}
+
+		if policy.BaseDelay > 0 {
+			o.Retry.BaseDelay = policy.BaseDelay
+		}
+
+		if policy.MaxDelay > 0 {
+			o.Retry.MaxDelay = policy.MaxDelay
+		}
+	}
+}
+
+func WithWireLogBody(limit int) Option {
+	return func(o *Options) {
+		o.WireLogBody = limit
+	}
+}
+
+func WithParams(params json.RawMessage) Option {
+	return func(o *Options) {
+		o.Params = params
+	}
+}
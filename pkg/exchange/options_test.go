@@ -0,0 +1,66 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type roundTripper struct{}
+
+func (roundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, http.ErrNotSupported
+}
+
+func TestHTTPClient(t *testing.T) {
+	proxy, _ := url.Parse("http://127.0.0.1:3128")
+	tlsConfig := &tls.Config{ServerName: "example.com"}
+	custom := &http.Transport{}
+
+	cli := NewOptions(Options{}, WithTransport(custom), WithProxy(proxy), WithTLSConfig(tlsConfig)).HTTPClient()
+
+	obj, ok := cli.Transport.(*http.Transport)
+	if !ok || obj == custom {
+		t.Fatalf("transport = %T, want a clone of the custom transport", cli.Transport)
+	}
+
+	if got, _ := obj.Proxy(&http.Request{URL: proxy}); got.String() != proxy.String() {
+		t.Errorf("proxy = %v, want %v", got, proxy)
+	}
+
+	if obj.TLSClientConfig != tlsConfig {
+		t.Error("tls config was not applied")
+	}
+
+	if custom.Proxy != nil || custom.TLSClientConfig == tlsConfig {
+		t.Error("custom transport was modified")
+	}
+
+	if cli = NewOptions(Options{}, WithTransport(custom)).HTTPClient(); cli.Transport != custom {
+		t.Error("custom transport without proxy or tls was replaced")
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	proxy, _ := url.Parse("http://127.0.0.1:3128")
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  bool
+	}{
+		{name: "default", opts: []Option{WithProxy(proxy)}},
+		{name: "http_transport", opts: []Option{WithTransport(&http.Transport{}), WithProxy(proxy)}},
+		{name: "round_tripper", opts: []Option{WithTransport(roundTripper{})}},
+		{name: "round_tripper_proxy", opts: []Option{WithTransport(roundTripper{}), WithProxy(proxy)}, err: true},
+		{name: "round_tripper_tls", opts: []Option{WithTransport(roundTripper{}), WithTLSConfig(&tls.Config{})}, err: true},
+		{name: "client_proxy", opts: []Option{WithHTTPClient(http.DefaultClient), WithProxy(proxy)}, err: true},
+	}
+
+	for _, tt := range tests {
+		if err := NewOptions(Options{}, tt.opts...).Validate(); (err != nil) != tt.err {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.err)
+		}
+	}
+}
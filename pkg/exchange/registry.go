@@ -0,0 +1,92 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type Factory func(opts ...Option) (Exchange, error)
+
+var (
+	registryMu = new(sync.Mutex)
+	registry   = make(map[string]Factory)
+	optIn      = make(map[string]bool)
+)
+
+func Register(exchangeID string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[exchangeID]; ok {
+		panic("exchange: Register called twice for " + exchangeID)
+	}
+
+	registry[exchangeID] = factory
+}
+
+// RegisterOptIn registers an exchange that is only enabled when it is listed in the config.
+func RegisterOptIn(exchangeID string, factory Factory) {
+	Register(exchangeID, factory)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	optIn[exchangeID] = true
+}
+
+func New(exchangeID string, opts ...Option) (Exchange, error) {
+	registryMu.Lock()
+	factory, ok := registry[exchangeID]
+	registryMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange: %s", exchangeID)
+	}
+
+	if err := NewOptions(Options{}, opts...).Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", exchangeID, err)
+	}
+
+	return factory(opts...)
+}
+
+func Registered(exchangeID string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	_, ok := registry[exchangeID]
+
+	return ok
+}
+
+func Names() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	var result []string
+
+	for exchangeID := range registry {
+		result = append(result, exchangeID)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func DefaultNames() []string {
+	var result []string
+
+	for _, exchangeID := range Names() {
+		registryMu.Lock()
+		skip := optIn[exchangeID]
+		registryMu.Unlock()
+
+		if !skip {
+			result = append(result, exchangeID)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,100 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"exchanges/pkg/breaker"
+	"math/rand"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil || attempt+1 >= policy.MaxAttempts || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := policy.backoff(attempt)
+
+		var e *Error
+
+		if errors.As(err, &e) && e.RetryAfter > 0 {
+			if e.RetryAfter > policy.MaxDelay {
+				return err
+			}
+
+			delay = e.RetryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryGuarded runs fn under policy and reports the outcome to brk and health.
+// An upstream failure seen before the caller gave up still counts against the venue,
+// brk is only released without a verdict when the caller gave up first.
+func RetryGuarded(ctx context.Context, policy RetryPolicy, brk *breaker.Breaker, health *HealthTracker, fn func() error) error {
+	var failure error
+
+	err := Retry(ctx, policy, func() error {
+		err := fn()
+
+		if ctx.Err() == nil && IsFailure(err) {
+			failure = err
+		}
+
+		return err
+	})
+
+	if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+		if failure == nil {
+			brk.Cancel()
+			return err
+		}
+
+		health.Observe(failure)
+		brk.Done(false)
+
+		return err
+	}
+
+	health.Observe(err)
+	brk.Done(!IsFailure(err))
+
+	return err
+}
+
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrUpstreamUnavailable) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTimeout)
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	half := delay / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,128 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"exchanges/pkg/breaker"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+		want   time.Duration
+	}{
+		{name: "none", status: http.StatusServiceUnavailable},
+		{name: "seconds", status: http.StatusTooManyRequests, header: "3", want: time.Second * 3},
+		{name: "date", status: http.StatusTooManyRequests, header: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), want: time.Minute},
+		{name: "invalid", status: http.StatusTooManyRequests, header: "soon"},
+		{name: "forbidden", status: http.StatusForbidden, want: banRetryAfter},
+		{name: "teapot", status: http.StatusTeapot, header: "1", want: banRetryAfter},
+	}
+
+	for _, tt := range tests {
+		rsp := &http.Response{StatusCode: tt.status, Header: make(http.Header)}
+
+		if len(tt.header) > 0 {
+			rsp.Header.Set("Retry-After", tt.header)
+		}
+
+		if got := RetryAfter(rsp); got > tt.want || got < tt.want-time.Second {
+			t.Errorf("%s: RetryAfter = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond * 50}
+
+	tests := []struct {
+		name     string
+		err      *Error
+		attempts int
+		minTime  time.Duration
+	}{
+		{name: "retryable", err: NewError(ErrUpstreamUnavailable, "fake", "", "down"), attempts: 3},
+		{name: "not_retryable", err: NewError(ErrPairNotFound, "fake", "", "missing"), attempts: 1},
+		{name: "retry_after", err: NewError(ErrRateLimited, "fake", "", "slow down").WithRetryAfter(time.Millisecond * 20), attempts: 3, minTime: time.Millisecond * 40},
+		{name: "retry_after_too_long", err: NewError(ErrRateLimited, "fake", "", "banned").WithRetryAfter(time.Minute), attempts: 1},
+	}
+
+	for _, tt := range tests {
+		attempts := 0
+		start := time.Now()
+
+		err := Retry(context.Background(), policy, func() error {
+			attempts++
+			return tt.err
+		})
+		if err != tt.err {
+			t.Errorf("%s: err = %v", tt.name, err)
+		}
+
+		if attempts != tt.attempts {
+			t.Errorf("%s: attempts = %d, want %d", tt.name, attempts, tt.attempts)
+		}
+
+		if elapsed := time.Since(start); elapsed < tt.minTime {
+			t.Errorf("%s: took %v, want at least %v", tt.name, elapsed, tt.minTime)
+		}
+	}
+}
+
+func TestRetryGuarded(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	tests := []struct {
+		name       string
+		errs       []error
+		wantState  string
+		wantHealth string
+	}{
+		{name: "success", errs: []error{nil}, wantState: breaker.StateClosed, wantHealth: HealthHealthy},
+		{name: "not_found", errs: []error{NewError(ErrPairNotFound, "fake", "", "missing")}, wantState: breaker.StateClosed, wantHealth: HealthHealthy},
+		{name: "caller_gave_up", errs: []error{context.Canceled}, wantState: breaker.StateClosed, wantHealth: HealthUnknown},
+		{name: "timeout_then_caller_gave_up", errs: []error{NewError(ErrTimeout, "fake", "", "timeout"), context.Canceled}, wantState: breaker.StateOpen, wantHealth: HealthDegraded},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			brk := breaker.NewBreaker(breaker.Config{FailureThreshold: 1})
+			health := NewHealthTracker()
+
+			if err := brk.Allow(); err != nil {
+				t.Fatal(err)
+			}
+
+			attempt := 0
+
+			_ = RetryGuarded(ctx, policy, brk, health, func() error {
+				err := tt.errs[attempt]
+				attempt++
+
+				if errors.Is(err, context.Canceled) {
+					cancel()
+				}
+
+				return err
+			})
+
+			if state := brk.State(); state != tt.wantState {
+				t.Errorf("breaker = %s, want %s", state, tt.wantState)
+			}
+
+			if status := health.Health().Status; status != tt.wantHealth {
+				t.Errorf("health = %s, want %s", status, tt.wantHealth)
+			}
+		})
+	}
+}
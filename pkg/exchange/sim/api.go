@@ -0,0 +1,73 @@
+package sim
+
+import (
+	"exchanges/pkg/exchange"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+func init() {
+	exchange.RegisterOptIn(exchangeID, func(opts ...exchange.Option) (exchange.Exchange, error) {
+		return NewAPI(opts...)
+	})
+}
+
+func NewAPI(opts ...exchange.Option) (*API, error) {
+	return newAPI(time.Now, opts...)
+}
+
+// newAPI builds the simulator on clock, which drives the walk; tests pass a manual one.
+func newAPI(clock func() time.Time, opts ...exchange.Option) (*API, error) {
+	cfg := exchange.NewOptions(exchange.Options{}, opts...)
+
+	params, err := ParseParams(cfg.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock()
+	step := time.Duration(params.Step)
+
+	obj := &API{
+		mu:       new(sync.Mutex),
+		rnd:      rand.New(rand.NewSource(params.Seed)),
+		jitter:   rand.New(rand.NewSource(params.Seed + 1)),
+		clock:    clock,
+		health:   exchange.NewHealthTracker(),
+		levels:   params.Levels,
+		step:     step,
+		scenario: params.Scenario,
+		halted:   make(map[string]bool),
+		markets:  make(map[string]*market),
+	}
+
+	for _, pairID := range params.Scenario.Halted {
+		obj.halted[pairID] = true
+	}
+
+	for _, row := range params.Pairs {
+		m := newMarket(row, now.Add(-step*defaultTrades))
+		m.advance(obj.rnd, now, step, obj.halted[row.Id], &obj.seq)
+
+		obj.markets[row.Id] = m
+		obj.order = append(obj.order, row.Id)
+	}
+
+	return obj, nil
+}
+
+type API struct {
+	mu       *sync.Mutex
+	rnd      *rand.Rand
+	jitter   *rand.Rand
+	clock    func() time.Time
+	health   *exchange.HealthTracker
+	levels   int
+	step     time.Duration
+	scenario Scenario
+	halted   map[string]bool
+	markets  map[string]*market
+	order    []string
+	seq      uint64
+}
@@ -0,0 +1,17 @@
+package sim
+
+import (
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	exchangetest.Run(t, exchangetest.Suite{
+		New: func(t *testing.T, _ string) exchange.Exchange {
+			return newTestAPI(t, `{"seed": 7}`)
+		},
+		Pair:        "BTC-USDT",
+		UnknownPair: "NOPE-USDT",
+	})
+}
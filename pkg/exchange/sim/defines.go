@@ -0,0 +1,39 @@
+package sim
+
+import (
+	"exchanges/pkg/exchange"
+	"time"
+)
+
+const (
+	exchangeID = "sim"
+
+	defaultSeed       = 1
+	defaultLevels     = 20
+	defaultStep       = time.Second
+	defaultVolatility = 0.0005
+	defaultSpread     = 0.0002
+	defaultTrades     = 100
+
+	maxStepsPerCall = 3600
+	day             = time.Hour * 24
+
+	sideBuy  = "buy"
+	sideSell = "sell"
+)
+
+var (
+	errorKinds = map[string]error{
+		"":                               exchange.ErrUpstreamUnavailable,
+		exchange.CodeUpstreamUnavailable: exchange.ErrUpstreamUnavailable,
+		exchange.CodeBadResponse:         exchange.ErrBadResponse,
+		exchange.CodeRateLimited:         exchange.ErrRateLimited,
+		exchange.CodeTimeout:             exchange.ErrTimeout,
+	}
+
+	defaultPairs = []PairParams{
+		{Id: "BTC-USDT", Base: "BTC", Quote: "USDT", Price: 30000, Tick: "0.01", Lot: "0.00001"},
+		{Id: "ETH-USDT", Base: "ETH", Quote: "USDT", Price: 2000, Tick: "0.01", Lot: "0.0001"},
+		{Id: "SOL-USDT", Base: "SOL", Quote: "USDT", Price: 20, Tick: "0.001", Lot: "0.01"},
+	}
+)
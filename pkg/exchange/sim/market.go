@@ -0,0 +1,179 @@
+package sim
+
+import (
+	"exchanges/pkg/exchange"
+	"github.com/shopspring/decimal"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+type market struct {
+	pair       PairParams
+	tick       decimal.Decimal
+	lot        decimal.Decimal
+	volatility float64
+	spread     float64
+	mid        float64
+	volume     float64
+	trades     []exchange.Trade
+	updated    time.Time
+	sequence   int64
+}
+
+func newMarket(pair PairParams, now time.Time) *market {
+	m := &market{
+		pair:       pair,
+		tick:       decimalOr(pair.Tick, "0.01"),
+		lot:        decimalOr(pair.Lot, "0.0001"),
+		volatility: pair.Volatility,
+		spread:     pair.Spread,
+		mid:        pair.Price,
+		volume:     1e6 / pair.Price,
+		updated:    now,
+	}
+
+	if m.volatility <= 0 {
+		m.volatility = defaultVolatility
+	}
+
+	if m.spread <= 0 {
+		m.spread = defaultSpread
+	}
+
+	return m
+}
+
+func decimalOr(value, fallback string) decimal.Decimal {
+	if d, err := decimal.NewFromString(value); err == nil && d.IsPositive() {
+		return d
+	}
+
+	return decimal.RequireFromString(fallback)
+}
+
+// advance moves the random walk forward by the steps elapsed since the last update.
+func (m *market) advance(rnd *rand.Rand, now time.Time, step time.Duration, halted bool, seq *uint64) {
+	steps := int(now.Sub(m.updated) / step)
+	if steps <= 0 {
+		return
+	}
+
+	if steps > maxStepsPerCall {
+		m.updated = now.Add(-step * maxStepsPerCall)
+		steps = maxStepsPerCall
+	}
+
+	for i := 0; i < steps; i++ {
+		m.updated = m.updated.Add(step)
+		m.sequence++
+		m.mid *= math.Exp(m.volatility * rnd.NormFloat64())
+		m.volume *= math.Exp(-float64(step) / float64(day))
+
+		if halted {
+			continue
+		}
+
+		n := rnd.Intn(4)
+
+		for k := 1; k <= n; k++ {
+			*seq++
+			m.trade(rnd, *seq, m.updated.Add(-step+step*time.Duration(k)/time.Duration(n+1)))
+		}
+	}
+}
+
+func (m *market) trade(rnd *rand.Rand, id uint64, at time.Time) {
+	ask, bid := m.top()
+
+	trade := exchange.Trade{
+		Id:     strconv.FormatUint(id, 10),
+		Price:  bid,
+		Amount: m.amount(rnd, 0.2),
+		Side:   sideSell,
+		Time:   at,
+	}
+
+	if rnd.Intn(2) == 0 {
+		trade.Price = ask
+		trade.Side = sideBuy
+	}
+
+	amount, _ := trade.Amount.Float64()
+	m.volume += amount
+
+	m.trades = append(m.trades, trade)
+
+	if len(m.trades) > defaultTrades {
+		m.trades = m.trades[len(m.trades)-defaultTrades:]
+	}
+}
+
+func (m *market) top() (decimal.Decimal, decimal.Decimal) {
+	half := m.mid * m.spread / 2
+
+	ask := decimal.NewFromFloat(m.mid + half).Div(m.tick).Ceil().Mul(m.tick)
+	bid := decimal.NewFromFloat(m.mid - half).Div(m.tick).Floor().Mul(m.tick)
+
+	if !ask.GreaterThan(bid) {
+		ask = bid.Add(m.tick)
+	}
+
+	if !bid.IsPositive() {
+		bid = m.tick
+		ask = bid.Add(m.tick)
+	}
+
+	return ask, bid
+}
+
+// amount returns a size worth about scale * 5000 quote units, at least one lot.
+func (m *market) amount(rnd *rand.Rand, scale float64) decimal.Decimal {
+	value := 5000 * scale * (0.2 + rnd.ExpFloat64()) / m.mid
+
+	lots := decimal.NewFromFloat(value).Div(m.lot).Round(0)
+	if lots.LessThan(decimal.NewFromInt(1)) {
+		lots = decimal.NewFromInt(1)
+	}
+
+	return lots.Mul(m.lot)
+}
+
+func (m *market) book(rnd *rand.Rand, levels int, crossed bool) exchange.OrderBook {
+	ask, bid := m.top()
+
+	if crossed {
+		bid = ask.Add(m.tick.Mul(decimal.NewFromInt(int64(1 + rnd.Intn(5)))))
+	}
+
+	gap := decimal.NewFromFloat(m.mid * 0.00005).Div(m.tick).Ceil().Mul(m.tick)
+	if gap.LessThan(m.tick) {
+		gap = m.tick
+	}
+
+	book := exchange.OrderBook{
+		Ask: make([][]decimal.Decimal, 0, levels),
+		Bid: make([][]decimal.Decimal, 0, levels),
+	}
+
+	for i := 0; i < levels; i++ {
+		scale := 1 + float64(i)*0.3
+
+		book.Ask = append(book.Ask, []decimal.Decimal{ask, m.amount(rnd, scale)})
+		book.Bid = append(book.Bid, []decimal.Decimal{bid, m.amount(rnd, scale)})
+
+		ask = ask.Add(gap.Mul(decimal.NewFromInt(int64(1 + rnd.Intn(3)))))
+		bid = bid.Sub(gap.Mul(decimal.NewFromInt(int64(1 + rnd.Intn(3)))))
+
+		if !bid.IsPositive() {
+			break
+		}
+	}
+
+	return book
+}
+
+func (m *market) volumeDecimal() decimal.Decimal {
+	return decimal.NewFromFloat(m.volume).Div(m.lot).Floor().Mul(m.lot)
+}
@@ -0,0 +1,179 @@
+package sim
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/metrics"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+func (a *API) GetID() string {
+	return exchangeID
+}
+
+func (a *API) Health() exchange.Health {
+	return a.health.Health()
+}
+
+func (a *API) Ping(ctx context.Context) error {
+	return a.call(ctx, "ping", func() error {
+		return nil
+	})
+}
+
+func (a *API) GetPairs(ctx context.Context) ([]exchange.Pair, error) {
+	var result []exchange.Pair
+
+	err := a.call(ctx, "pairs", func() error {
+		for _, pairID := range a.order {
+			if a.halted[pairID] {
+				continue
+			}
+
+			m := a.markets[pairID]
+			ask, bid := m.top()
+
+			result = append(result, exchange.Pair{
+				Id:         pairID,
+				BaseAsset:  m.pair.Base,
+				QuoteAsset: m.pair.Quote,
+				Ask:        ask,
+				Bid:        bid,
+				Volume:     m.volumeDecimal(),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *API) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBook, error) {
+	if len(pairID) == 0 {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrInvalidArgument, exchangeID, "", "empty pair id")
+	}
+
+	var result exchange.OrderBook
+
+	err := a.call(ctx, "orderbook", func() error {
+		m, ok := a.markets[pairID]
+		if !ok {
+			return exchange.NewError(exchange.ErrPairNotFound, exchangeID, "", "unknown pair "+pairID)
+		}
+
+		if a.halted[pairID] {
+			result = exchange.OrderBook{Ask: [][]decimal.Decimal{}, Bid: [][]decimal.Decimal{}, Timestamp: m.updated.UnixMilli(), Sequence: m.sequence}
+			return nil
+		}
+
+		crossed := a.rnd.Float64() < a.scenario.CrossedRate
+		result = m.book(a.rnd, a.levels, crossed)
+		result.Timestamp = m.updated.UnixMilli()
+		result.Sequence = m.sequence
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (a *API) GetTrades(ctx context.Context, pairID string) ([]exchange.Trade, error) {
+	if len(pairID) == 0 {
+		return nil, exchange.NewError(exchange.ErrInvalidArgument, exchangeID, "", "empty pair id")
+	}
+
+	var result []exchange.Trade
+
+	err := a.call(ctx, "trades", func() error {
+		m, ok := a.markets[pairID]
+		if !ok {
+			return exchange.NewError(exchange.ErrPairNotFound, exchangeID, "", "unknown pair "+pairID)
+		}
+
+		result = make([]exchange.Trade, 0, len(m.trades))
+
+		for i := len(m.trades) - 1; i >= 0; i-- {
+			result = append(result, m.trades[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// call applies the scenario (latency, errors, maintenance), advances the
+// markets and runs fn under the lock.
+func (a *API) call(ctx context.Context, endpoint string, fn func() error) error {
+	start := time.Now()
+
+	err := a.wait(ctx)
+
+	if err == nil {
+		err = func() error {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+
+			if err := a.inject(); err != nil {
+				return err
+			}
+
+			now := a.clock()
+
+			for _, pairID := range a.order {
+				a.markets[pairID].advance(a.rnd, now, a.step, a.halted[pairID], &a.seq)
+			}
+
+			return fn()
+		}()
+	}
+
+	metrics.ObserveUpstream(exchangeID, endpoint, exchange.ResultCode(err), time.Since(start))
+	a.health.Observe(err)
+
+	return err
+}
+
+func (a *API) wait(ctx context.Context) error {
+	delay := time.Duration(a.scenario.Latency)
+
+	if a.scenario.Jitter > 0 {
+		a.mu.Lock()
+		delay += time.Duration(a.jitter.Int63n(int64(a.scenario.Jitter)))
+		a.mu.Unlock()
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return exchange.WrapError(exchange.TransportKind(ctx.Err()), exchangeID, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (a *API) inject() error {
+	if a.scenario.Maintenance {
+		return exchange.NewError(exchange.ErrUpstreamUnavailable, exchangeID, "", "simulated maintenance")
+	}
+
+	if a.scenario.ErrorRate > 0 && a.rnd.Float64() < a.scenario.ErrorRate {
+		return exchange.NewError(errorKinds[a.scenario.ErrorCode], exchangeID, "", "simulated "+exchange.ErrorCode(errorKinds[a.scenario.ErrorCode])+" error")
+	}
+
+	return nil
+}
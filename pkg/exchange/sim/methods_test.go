@@ -0,0 +1,291 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/exchange/exchangetest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	mu  *sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{
+		mu:  new(sync.Mutex),
+		now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestAPI(t *testing.T, params string) *API {
+	t.Helper()
+
+	obj, err := NewAPI(exchange.WithParams([]byte(params)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return obj
+}
+
+func newClockAPI(t *testing.T, clock *testClock, params string) *API {
+	t.Helper()
+
+	obj, err := newAPI(clock.Now, exchange.WithParams([]byte(params)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return obj
+}
+
+func TestNewAPIParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: `{}`},
+		{name: "pairs", params: `{"pairs": [{"id": "X-Y", "base": "X", "quote": "Y", "price": 1.5, "tick": "0.0001", "lot": "1"}]}`},
+		{name: "bad_json", params: `{"seed": "x"}`, wantErr: true},
+		{name: "no_price", params: `{"pairs": [{"id": "X-Y", "base": "X", "quote": "Y"}]}`, wantErr: true},
+		{name: "duplicate", params: `{"pairs": [{"id": "X-Y", "base": "X", "quote": "Y", "price": 1}, {"id": "X-Y", "base": "X", "quote": "Y", "price": 1}]}`, wantErr: true},
+		{name: "bad_tick", params: `{"pairs": [{"id": "X-Y", "base": "X", "quote": "Y", "price": 1, "tick": "0"}]}`, wantErr: true},
+		{name: "bad_rate", params: `{"scenario": {"error_rate": 2}}`, wantErr: true},
+		{name: "bad_code", params: `{"scenario": {"error_code": "nope"}}`, wantErr: true},
+		{name: "bad_latency", params: `{"scenario": {"latency": "soon"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAPI(exchange.WithParams([]byte(tt.params)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+	clock := newTestClock()
+	a := newClockAPI(t, clock, `{"seed": 42}`)
+	b := newClockAPI(t, clock, `{"seed": 42, "scenario": {"jitter": "1ms"}}`)
+	c := newClockAPI(t, clock, `{"seed": 43}`)
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		clock.Add(time.Second * time.Duration(i*5))
+
+		bookA, err := a.GetOrderBook(ctx, "ETH-USDT")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bookB, err := b.GetOrderBook(ctx, "ETH-USDT")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bookC, err := c.GetOrderBook(ctx, "ETH-USDT")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bookA.Timestamp != clock.Now().UnixMilli() {
+			t.Fatalf("timestamp = %d, want the clock %d", bookA.Timestamp, clock.Now().UnixMilli())
+		}
+
+		if !reflect.DeepEqual(bookA, bookB) {
+			t.Fatalf("call %d: jitter changed the walk:\n%v\n%v", i, bookA, bookB)
+		}
+
+		if reflect.DeepEqual(bookA.Ask, bookC.Ask) {
+			t.Fatalf("call %d: different seeds gave the same book", i)
+		}
+
+		if len(bookA.Ask) != defaultLevels || len(bookA.Bid) != defaultLevels {
+			t.Fatalf("levels = %d/%d, want %d", len(bookA.Ask), len(bookA.Bid), defaultLevels)
+		}
+	}
+
+	tradesA, _ := a.GetTrades(ctx, "ETH-USDT")
+	tradesB, _ := b.GetTrades(ctx, "ETH-USDT")
+
+	if !reflect.DeepEqual(tradesA, tradesB) {
+		t.Fatal("same seed and clock gave different trades")
+	}
+}
+
+func TestClock(t *testing.T) {
+	clock := newTestClock()
+	obj := newClockAPI(t, clock, `{"step": "1s"}`)
+	ctx := context.Background()
+
+	first, err := obj.GetOrderBook(ctx, "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := obj.GetPairs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := obj.GetPairs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(pairs, again) {
+		t.Fatal("pairs moved while the clock stood still")
+	}
+
+	clock.Add(time.Millisecond * 2500)
+
+	second, err := obj.GetOrderBook(ctx, "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second.Sequence != first.Sequence+2 {
+		t.Fatalf("sequence = %d after 2.5 steps from %d, want +2", second.Sequence, first.Sequence)
+	}
+
+	if want := first.Timestamp + 2000; second.Timestamp != want {
+		t.Fatalf("timestamp = %d, want %d", second.Timestamp, want)
+	}
+
+	clock.Add(time.Hour * 24)
+
+	third, err := obj.GetOrderBook(ctx, "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if third.Sequence != second.Sequence+maxStepsPerCall || third.Timestamp != clock.Now().UnixMilli() {
+		t.Fatalf("after a day: sequence %d, timestamp %d, want +%d steps up to now", third.Sequence, third.Timestamp, maxStepsPerCall)
+	}
+}
+
+func TestTrades(t *testing.T) {
+	obj := newTestAPI(t, `{}`)
+
+	trades, err := obj.GetTrades(context.Background(), "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trades) == 0 {
+		t.Fatal("no trades")
+	}
+
+	for i, row := range trades {
+		if row.Side != sideBuy && row.Side != sideSell || !row.Price.IsPositive() || !row.Amount.IsPositive() {
+			t.Fatalf("trade %d is invalid: %+v", i, row)
+		}
+
+		if i > 0 && !row.Time.Before(trades[i-1].Time) {
+			t.Fatalf("trade %d is newer than trade %d", i, i-1)
+		}
+	}
+
+	if _, err = obj.GetTrades(context.Background(), "NOPE"); !errors.Is(err, exchange.ErrPairNotFound) {
+		t.Fatalf("error = %v, want pair not found", err)
+	}
+}
+
+func TestScenario(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("halted", func(t *testing.T) {
+		obj := newTestAPI(t, `{"scenario": {"halted": ["ETH-USDT"]}}`)
+
+		pairs, err := obj.GetPairs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, row := range pairs {
+			if row.Id == "ETH-USDT" {
+				t.Fatal("halted pair is listed")
+			}
+		}
+
+		book, err := obj.GetOrderBook(ctx, "ETH-USDT")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(book.Ask) != 0 || len(book.Bid) != 0 {
+			t.Fatalf("halted book = %v, want empty", book)
+		}
+	})
+
+	t.Run("crossed", func(t *testing.T) {
+		obj := newTestAPI(t, `{"scenario": {"crossed_rate": 1}}`)
+
+		book, err := obj.GetOrderBook(ctx, "BTC-USDT")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		book.Sort()
+
+		if err = exchangetest.CheckOrderBook(book); err == nil {
+			t.Fatal("book is not crossed")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		obj := newTestAPI(t, `{"scenario": {"error_rate": 1, "error_code": "rate_limited"}}`)
+
+		if _, err := obj.GetPairs(ctx); !errors.Is(err, exchange.ErrRateLimited) {
+			t.Fatalf("error = %v, want rate limited", err)
+		}
+
+		if health := obj.Health(); health.Failures != 1 {
+			t.Fatalf("failures = %d, want 1", health.Failures)
+		}
+	})
+
+	t.Run("maintenance", func(t *testing.T) {
+		obj := newTestAPI(t, `{"scenario": {"maintenance": true}}`)
+
+		if err := obj.Ping(ctx); !errors.Is(err, exchange.ErrUpstreamUnavailable) {
+			t.Fatalf("error = %v, want upstream unavailable", err)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		obj := newTestAPI(t, `{"scenario": {"latency": "1s"}}`)
+
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond*20)
+		defer cancel()
+
+		if _, err := obj.GetPairs(ctx); !errors.Is(err, exchange.ErrTimeout) {
+			t.Fatalf("error = %v, want timeout", err)
+		}
+	})
+}
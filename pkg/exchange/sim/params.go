@@ -0,0 +1,101 @@
+package sim
+
+import (
+	"encoding/json"
+	"exchanges/pkg/duration"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+type Params struct {
+	Seed     int64             `json:"seed,omitempty"`
+	Levels   int               `json:"levels,omitempty"`
+	Step     duration.Duration `json:"step,omitempty"`
+	Pairs    []PairParams      `json:"pairs,omitempty"`
+	Scenario Scenario          `json:"scenario"`
+}
+
+type PairParams struct {
+	Id         string  `json:"id"`
+	Base       string  `json:"base"`
+	Quote      string  `json:"quote"`
+	Price      float64 `json:"price"`
+	Tick       string  `json:"tick,omitempty"`
+	Lot        string  `json:"lot,omitempty"`
+	Volatility float64 `json:"volatility,omitempty"`
+	Spread     float64 `json:"spread,omitempty"`
+}
+
+type Scenario struct {
+	Latency     duration.Duration `json:"latency,omitempty"`
+	Jitter      duration.Duration `json:"jitter,omitempty"`
+	ErrorRate   float64           `json:"error_rate,omitempty"`
+	ErrorCode   string            `json:"error_code,omitempty"`
+	CrossedRate float64           `json:"crossed_rate,omitempty"`
+	Halted      []string          `json:"halted,omitempty"`
+	Maintenance bool              `json:"maintenance,omitempty"`
+}
+
+func ParseParams(raw json.RawMessage) (Params, error) {
+	var p Params
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return p, fmt.Errorf("sim params: %w", err)
+		}
+	}
+
+	if p.Seed == 0 {
+		p.Seed = defaultSeed
+	}
+
+	if p.Levels <= 0 {
+		p.Levels = defaultLevels
+	}
+
+	if p.Step <= 0 {
+		p.Step = duration.Duration(defaultStep)
+	}
+
+	if len(p.Pairs) == 0 {
+		p.Pairs = defaultPairs
+	}
+
+	if p.Scenario.ErrorRate < 0 || p.Scenario.ErrorRate > 1 || p.Scenario.CrossedRate < 0 || p.Scenario.CrossedRate > 1 {
+		return p, fmt.Errorf("sim params: error_rate and crossed_rate must be between 0 and 1")
+	}
+
+	if _, ok := errorKinds[p.Scenario.ErrorCode]; !ok {
+		return p, fmt.Errorf("sim params: unknown error_code %q", p.Scenario.ErrorCode)
+	}
+
+	seen := make(map[string]bool)
+
+	for i, row := range p.Pairs {
+		if len(row.Id) == 0 || len(row.Base) == 0 || len(row.Quote) == 0 {
+			return p, fmt.Errorf("sim params: pair %d: id, base and quote are required", i)
+		}
+
+		if seen[row.Id] {
+			return p, fmt.Errorf("sim params: pair %s: duplicate id", row.Id)
+		}
+
+		if row.Price <= 0 {
+			return p, fmt.Errorf("sim params: pair %s: price must be positive", row.Id)
+		}
+
+		for name, value := range map[string]string{"tick": row.Tick, "lot": row.Lot} {
+			if len(value) == 0 {
+				continue
+			}
+
+			if d, err := decimal.NewFromString(value); err != nil || !d.IsPositive() {
+				return p, fmt.Errorf("sim params: pair %s: %s must be a positive decimal", row.Id, name)
+			}
+		}
+
+		seen[row.Id] = true
+	}
+
+	return p, nil
+}
@@ -0,0 +1,31 @@
+package exchange
+
+import "sort"
+
+func SortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Id < pairs[j].Id
+	})
+}
+
+func (o OrderBook) Truncate(depth int) OrderBook {
+	if depth > 0 && len(o.Ask) > depth {
+		o.Ask = o.Ask[:depth]
+	}
+
+	if depth > 0 && len(o.Bid) > depth {
+		o.Bid = o.Bid[:depth]
+	}
+
+	return o
+}
+
+func (o OrderBook) Sort() {
+	sort.Slice(o.Ask, func(i, j int) bool {
+		return o.Ask[i][0].LessThan(o.Ask[j][0])
+	})
+
+	sort.Slice(o.Bid, func(i, j int) bool {
+		return o.Bid[i][0].GreaterThan(o.Bid[j][0])
+	})
+}
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"github.com/shopspring/decimal"
+	"time"
 )
 
 type Pair struct {
@@ -10,9 +11,27 @@ type Pair struct {
 	QuoteAsset string          `json:"quote_asset"`
 	Ask        decimal.Decimal `json:"ask"`
 	Bid        decimal.Decimal `json:"bid"`
+	Volume     decimal.Decimal `json:"volume"`
+	Stale      bool            `json:"stale,omitempty"`
+	Flags      []string        `json:"flags,omitempty"`
 }
 
 type OrderBook struct {
 	Ask [][]decimal.Decimal `json:"ask"`
 	Bid [][]decimal.Decimal `json:"bid"`
+	// Timestamp is the venue time of the snapshot in unix milliseconds, 0 when unknown.
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Sequence grows with every update of the venue book, UpdateID is the venue's
+	// own update id where it has one; both are 0 when unknown.
+	Sequence int64    `json:"sequence,omitempty"`
+	UpdateID int64    `json:"update_id,omitempty"`
+	Flags    []string `json:"flags,omitempty"`
+}
+
+type Trade struct {
+	Id     string          `json:"id"`
+	Price  decimal.Decimal `json:"price"`
+	Amount decimal.Decimal `json:"amount"`
+	Side   string          `json:"side"`
+	Time   time.Time       `json:"time"`
 }
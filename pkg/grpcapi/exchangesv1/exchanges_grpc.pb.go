@@ -0,0 +1,325 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: exchanges.proto
+
+package exchangesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	ExchangeService_ListExchanges_FullMethodName   = "/exchanges.v1.ExchangeService/ListExchanges"
+	ExchangeService_GetPairs_FullMethodName        = "/exchanges.v1.ExchangeService/GetPairs"
+	ExchangeService_GetOrderBook_FullMethodName    = "/exchanges.v1.ExchangeService/GetOrderBook"
+	ExchangeService_StreamOrderBook_FullMethodName = "/exchanges.v1.ExchangeService/StreamOrderBook"
+	ExchangeService_StreamTickers_FullMethodName   = "/exchanges.v1.ExchangeService/StreamTickers"
+)
+
+// ExchangeServiceClient is the client API for ExchangeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ExchangeService mirrors the /api/v1 HTTP routes.
+type ExchangeServiceClient interface {
+	ListExchanges(ctx context.Context, in *ListExchangesRequest, opts ...grpc.CallOption) (*ListExchangesResponse, error)
+	GetPairs(ctx context.Context, in *GetPairsRequest, opts ...grpc.CallOption) (*GetPairsResponse, error)
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*OrderBook, error)
+	// StreamOrderBook sends the order book every time it changes.
+	StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (ExchangeService_StreamOrderBookClient, error)
+	// StreamTickers sends best ask and bid of the requested pairs every time they change.
+	StreamTickers(ctx context.Context, in *StreamTickersRequest, opts ...grpc.CallOption) (ExchangeService_StreamTickersClient, error)
+}
+
+type exchangeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExchangeServiceClient(cc grpc.ClientConnInterface) ExchangeServiceClient {
+	return &exchangeServiceClient{cc}
+}
+
+func (c *exchangeServiceClient) ListExchanges(ctx context.Context, in *ListExchangesRequest, opts ...grpc.CallOption) (*ListExchangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListExchangesResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_ListExchanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetPairs(ctx context.Context, in *GetPairsRequest, opts ...grpc.CallOption) (*GetPairsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPairsResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetPairs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*OrderBook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderBook)
+	err := c.cc.Invoke(ctx, ExchangeService_GetOrderBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (ExchangeService_StreamOrderBookClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExchangeService_ServiceDesc.Streams[0], ExchangeService_StreamOrderBook_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &exchangeServiceStreamOrderBookClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExchangeService_StreamOrderBookClient interface {
+	Recv() (*OrderBook, error)
+	grpc.ClientStream
+}
+
+type exchangeServiceStreamOrderBookClient struct {
+	grpc.ClientStream
+}
+
+func (x *exchangeServiceStreamOrderBookClient) Recv() (*OrderBook, error) {
+	m := new(OrderBook)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *exchangeServiceClient) StreamTickers(ctx context.Context, in *StreamTickersRequest, opts ...grpc.CallOption) (ExchangeService_StreamTickersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExchangeService_ServiceDesc.Streams[1], ExchangeService_StreamTickers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &exchangeServiceStreamTickersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExchangeService_StreamTickersClient interface {
+	Recv() (*Tickers, error)
+	grpc.ClientStream
+}
+
+type exchangeServiceStreamTickersClient struct {
+	grpc.ClientStream
+}
+
+func (x *exchangeServiceStreamTickersClient) Recv() (*Tickers, error) {
+	m := new(Tickers)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ExchangeServiceServer is the server API for ExchangeService service.
+// All implementations must embed UnimplementedExchangeServiceServer
+// for forward compatibility
+//
+// ExchangeService mirrors the /api/v1 HTTP routes.
+type ExchangeServiceServer interface {
+	ListExchanges(context.Context, *ListExchangesRequest) (*ListExchangesResponse, error)
+	GetPairs(context.Context, *GetPairsRequest) (*GetPairsResponse, error)
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*OrderBook, error)
+	// StreamOrderBook sends the order book every time it changes.
+	StreamOrderBook(*StreamOrderBookRequest, ExchangeService_StreamOrderBookServer) error
+	// StreamTickers sends best ask and bid of the requested pairs every time they change.
+	StreamTickers(*StreamTickersRequest, ExchangeService_StreamTickersServer) error
+	mustEmbedUnimplementedExchangeServiceServer()
+}
+
+// UnimplementedExchangeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedExchangeServiceServer struct {
+}
+
+func (UnimplementedExchangeServiceServer) ListExchanges(context.Context, *ListExchangesRequest) (*ListExchangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExchanges not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetPairs(context.Context, *GetPairsRequest) (*GetPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPairs not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*OrderBook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedExchangeServiceServer) StreamOrderBook(*StreamOrderBookRequest, ExchangeService_StreamOrderBookServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrderBook not implemented")
+}
+func (UnimplementedExchangeServiceServer) StreamTickers(*StreamTickersRequest, ExchangeService_StreamTickersServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTickers not implemented")
+}
+func (UnimplementedExchangeServiceServer) mustEmbedUnimplementedExchangeServiceServer() {}
+
+// UnsafeExchangeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExchangeServiceServer will
+// result in compilation errors.
+type UnsafeExchangeServiceServer interface {
+	mustEmbedUnimplementedExchangeServiceServer()
+}
+
+func RegisterExchangeServiceServer(s grpc.ServiceRegistrar, srv ExchangeServiceServer) {
+	s.RegisterService(&ExchangeService_ServiceDesc, srv)
+}
+
+func _ExchangeService_ListExchanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExchangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).ListExchanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_ListExchanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).ListExchanges(ctx, req.(*ListExchangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetPairs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetPairs(ctx, req.(*GetPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_StreamOrderBook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrderBookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExchangeServiceServer).StreamOrderBook(m, &exchangeServiceStreamOrderBookServer{ServerStream: stream})
+}
+
+type ExchangeService_StreamOrderBookServer interface {
+	Send(*OrderBook) error
+	grpc.ServerStream
+}
+
+type exchangeServiceStreamOrderBookServer struct {
+	grpc.ServerStream
+}
+
+func (x *exchangeServiceStreamOrderBookServer) Send(m *OrderBook) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ExchangeService_StreamTickers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTickersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExchangeServiceServer).StreamTickers(m, &exchangeServiceStreamTickersServer{ServerStream: stream})
+}
+
+type ExchangeService_StreamTickersServer interface {
+	Send(*Tickers) error
+	grpc.ServerStream
+}
+
+type exchangeServiceStreamTickersServer struct {
+	grpc.ServerStream
+}
+
+func (x *exchangeServiceStreamTickersServer) Send(m *Tickers) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ExchangeService_ServiceDesc is the grpc.ServiceDesc for ExchangeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExchangeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchanges.v1.ExchangeService",
+	HandlerType: (*ExchangeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListExchanges",
+			Handler:    _ExchangeService_ListExchanges_Handler,
+		},
+		{
+			MethodName: "GetPairs",
+			Handler:    _ExchangeService_GetPairs_Handler,
+		},
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _ExchangeService_GetOrderBook_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOrderBook",
+			Handler:       _ExchangeService_StreamOrderBook_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTickers",
+			Handler:       _ExchangeService_StreamTickers_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "exchanges.proto",
+}
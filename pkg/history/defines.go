@@ -0,0 +1,24 @@
+package history
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	segmentDuration = time.Hour
+	segmentLayout   = "20060102T1504"
+	segmentExt      = ".jsonl.gz"
+	recordTimeout   = time.Second * 30
+	cleanupInterval = time.Minute * 10
+	maxLineSize     = 64 << 20
+)
+
+const (
+	KindPairs     = "pairs"
+	KindOrderBook = "orderbook"
+)
+
+var (
+	ErrNotFound = errors.New("snapshot not found")
+)
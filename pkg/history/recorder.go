@@ -0,0 +1,199 @@
+package history
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/logger"
+	"exchanges/pkg/quality"
+	"sort"
+	"sync"
+	"time"
+)
+
+func NewRecorder(store *Store, interval time.Duration) *Recorder {
+	return &Recorder{
+		mu:        new(sync.Mutex),
+		store:     store,
+		interval:  interval,
+		exchanges: make(map[string]exchange.Exchange),
+		books:     make(map[string][]string),
+		reset:     make(chan struct{}, 1),
+	}
+}
+
+type Recorder struct {
+	mu        *sync.Mutex
+	store     *Store
+	interval  time.Duration
+	exchanges map[string]exchange.Exchange
+	books     map[string][]string
+	validator *quality.Validator
+	reset     chan struct{}
+}
+
+func (r *Recorder) SetExchange(obj exchange.Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges[obj.GetID()] = obj
+}
+
+func (r *Recorder) RemoveExchange(exchangeID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.exchanges, exchangeID)
+}
+
+func (r *Recorder) SetOrderBooks(books map[string][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.books = books
+}
+
+// SetValidator makes the recorder store snapshots as validated by v, so history matches what the API served.
+func (r *Recorder) SetValidator(v *quality.Validator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.validator = v
+}
+
+func (r *Recorder) SetInterval(interval time.Duration) {
+	r.mu.Lock()
+	changed := interval != r.interval
+	r.interval = interval
+	r.mu.Unlock()
+
+	if changed {
+		select {
+		case r.reset <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.getInterval())
+	defer ticker.Stop()
+
+	r.record(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.reset:
+			ticker.Reset(r.getInterval())
+			continue
+		case <-ticker.C:
+		}
+
+		r.record(ctx)
+	}
+}
+
+func (r *Recorder) getInterval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.interval
+}
+
+func (r *Recorder) record(ctx context.Context) {
+	exchanges, books := r.targets()
+
+	wg := new(sync.WaitGroup)
+
+	for _, obj := range exchanges {
+		wg.Add(1)
+
+		go func(obj exchange.Exchange, pairs []string) {
+			defer wg.Done()
+
+			r.recordExchange(ctx, obj, pairs)
+		}(obj, books[obj.GetID()])
+	}
+
+	wg.Wait()
+}
+
+func (r *Recorder) recordExchange(ctx context.Context, obj exchange.Exchange, books []string) {
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(ctx, logger.NewRequestID()), recordTimeout)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	if err := r.recordPairs(ctx, obj); err != nil {
+		log.Warn("history record failed", "exchange", obj.GetID(), "kind", KindPairs, "error", err)
+	}
+
+	for _, pairID := range books {
+		if err := r.recordOrderBook(ctx, obj, pairID); err != nil {
+			log.Warn("history record failed", "exchange", obj.GetID(), "kind", KindOrderBook, "pair", pairID, "error", err)
+		}
+	}
+}
+
+func (r *Recorder) recordPairs(ctx context.Context, obj exchange.Exchange) error {
+	pairs, err := obj.GetPairs(ctx)
+	if err != nil {
+		return err
+	}
+
+	if v := r.getValidator(); v != nil {
+		pairs = v.Pairs(obj.GetID(), pairs)
+	}
+
+	exchange.SortPairs(pairs)
+
+	return r.store.Write(obj.GetID(), KindPairs, "", pairs)
+}
+
+func (r *Recorder) recordOrderBook(ctx context.Context, obj exchange.Exchange, pairID string) error {
+	book, err := obj.GetOrderBook(ctx, pairID)
+	if err != nil {
+		return err
+	}
+
+	if v := r.getValidator(); v != nil {
+		if book, err = v.OrderBook(obj.GetID(), pairID, book); err != nil {
+			return err
+		}
+	}
+
+	book.Sort()
+
+	return r.store.Write(obj.GetID(), KindOrderBook, pairID, book)
+}
+
+func (r *Recorder) getValidator() *quality.Validator {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.validator
+}
+
+func (r *Recorder) targets() ([]exchange.Exchange, map[string][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var exchanges []exchange.Exchange
+
+	for _, obj := range r.exchanges {
+		exchanges = append(exchanges, obj)
+	}
+
+	sort.Slice(exchanges, func(i, j int) bool {
+		return exchanges[i].GetID() < exchanges[j].GetID()
+	})
+
+	books := make(map[string][]string, len(r.books))
+
+	for exchangeID, pairs := range r.books {
+		books[exchangeID] = append([]string(nil), pairs...)
+	}
+
+	return exchanges, books
+}
@@ -0,0 +1,151 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/quality"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingExchange struct {
+	calls *atomic.Int32
+}
+
+func (e countingExchange) GetID() string {
+	return "fake"
+}
+
+func (e countingExchange) GetPairs(context.Context) ([]exchange.Pair, error) {
+	e.calls.Add(1)
+	return nil, nil
+}
+
+func (e countingExchange) GetOrderBook(context.Context, string) (exchange.OrderBook, error) {
+	return exchange.OrderBook{}, nil
+}
+
+func TestRecorderSetInterval(t *testing.T) {
+	store, err := NewStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = store.Close()
+	}()
+
+	obj := countingExchange{calls: new(atomic.Int32)}
+
+	recorder := NewRecorder(store, time.Hour)
+	recorder.SetExchange(obj)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go recorder.Run(ctx)
+
+	deadline := time.Now().Add(time.Second * 5)
+
+	for obj.calls.Load() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("recorder did not record on start")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	recorder.SetInterval(time.Millisecond * 10)
+
+	for obj.calls.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("recorder kept the old interval: %d records", obj.calls.Load())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type crossedExchange struct{}
+
+func (e crossedExchange) GetID() string {
+	return "fake"
+}
+
+func (e crossedExchange) GetPairs(context.Context) ([]exchange.Pair, error) {
+	return nil, nil
+}
+
+func (e crossedExchange) GetOrderBook(context.Context, string) (exchange.OrderBook, error) {
+	return exchange.OrderBook{
+		Ask: [][]decimal.Decimal{{decimal.NewFromInt(100), decimal.NewFromInt(1)}},
+		Bid: [][]decimal.Decimal{{decimal.NewFromInt(101), decimal.NewFromInt(1)}},
+	}, nil
+}
+
+func TestRecorderValidator(t *testing.T) {
+	tests := []struct {
+		mode      string
+		wantErr   bool
+		wantFlags []string
+	}{
+		{mode: quality.ModeFlag, wantFlags: []string{quality.RuleCrossed}},
+		{mode: quality.ModeReject, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.mode, func(t *testing.T) {
+			store, err := NewStore(t.TempDir(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				_ = store.Close()
+			}()
+
+			recorder := NewRecorder(store, time.Hour)
+			recorder.SetValidator(quality.NewValidator(quality.Config{Mode: tt.mode}))
+
+			err = recorder.recordOrderBook(context.Background(), crossedExchange{}, "BTC-USDT")
+
+			if tt.wantErr {
+				if !errors.Is(err, exchange.ErrBadData) {
+					t.Fatalf("error = %v, want %v", err, exchange.ErrBadData)
+				}
+
+				if _, err = store.Closest("fake", KindOrderBook, "BTC-USDT", time.Now()); !errors.Is(err, ErrNotFound) {
+					t.Fatalf("closest error = %v, want %v", err, ErrNotFound)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot, err := store.Closest("fake", KindOrderBook, "BTC-USDT", time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var book exchange.OrderBook
+
+			if err = json.Unmarshal(snapshot.Data, &book); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(book.Flags, tt.wantFlags) {
+				t.Fatalf("flags = %v, want %v", book.Flags, tt.wantFlags)
+			}
+		})
+	}
+}
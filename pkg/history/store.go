@@ -0,0 +1,416 @@
+package history
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func NewStore(dir string, retention time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	obj := &Store{
+		mu:        new(sync.Mutex),
+		dir:       dir,
+		retention: retention,
+		index:     make(map[string]map[series][]time.Time),
+	}
+
+	if err := obj.cleanup(); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+type Store struct {
+	mu        *sync.Mutex
+	dir       string
+	retention time.Duration
+	file      *os.File
+	gz        *gzip.Writer
+	segment   time.Time
+	index     map[string]map[series][]time.Time
+}
+
+type segment struct {
+	path  string
+	start time.Time
+}
+
+type series struct {
+	exchange string
+	kind     string
+	pair     string
+}
+
+func (s *Store) Write(exchangeID, kind, pairID string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	snapshot := Snapshot{
+		Time:     time.Now().UTC(),
+		Exchange: exchangeID,
+		Kind:     kind,
+		Pair:     pairID,
+		Data:     raw,
+	}
+
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if start := snapshot.Time.Truncate(segmentDuration); s.gz == nil || !start.Equal(s.segment) {
+		if err = s.rotate(start); err != nil {
+			return err
+		}
+	}
+
+	if _, err = s.gz.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	if err = s.gz.Flush(); err != nil {
+		return err
+	}
+
+	if times, ok := s.index[s.file.Name()]; ok {
+		key := series{exchange: exchangeID, kind: kind, pair: pairID}
+		times[key] = append(times[key], snapshot.Time)
+	}
+
+	return nil
+}
+
+func (s *Store) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			err := s.cleanup()
+			s.mu.Unlock()
+
+			if err != nil {
+				slog.Warn("history cleanup failed", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeSegment()
+}
+
+func (s *Store) Closest(exchangeID, kind, pairID string, at time.Time) (Snapshot, error) {
+	segments, err := s.segments()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segmentDistance(segments[i], at) < segmentDistance(segments[j], at)
+	})
+
+	var (
+		key   = series{exchange: exchangeID, kind: kind, pair: pairID}
+		path  string
+		found time.Time
+		best  time.Duration
+	)
+
+	for _, row := range segments {
+		if len(path) > 0 && segmentDistance(row, at) > best {
+			break
+		}
+
+		t, ok, err := s.closestIn(row.path, key, at)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		if distance := absDuration(t.Sub(at)); ok && (len(path) == 0 || distance < best) {
+			path, found, best = row.path, t, distance
+		}
+	}
+
+	if len(path) == 0 {
+		return Snapshot{}, ErrNotFound
+	}
+
+	var (
+		result Snapshot
+		ok     bool
+	)
+
+	err = scanSegment(path, func(snapshot Snapshot) {
+		if !ok && snapshot.Exchange == exchangeID && snapshot.Kind == kind && snapshot.Pair == pairID && snapshot.Time.Equal(found) {
+			result, ok = snapshot, true
+		}
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if !ok {
+		return Snapshot{}, ErrNotFound
+	}
+
+	return result, nil
+}
+
+func (s *Store) closestIn(path string, key series, at time.Time) (time.Time, bool, error) {
+	s.mu.Lock()
+	_, ok := s.index[path]
+	s.mu.Unlock()
+
+	if !ok {
+		times := make(map[series][]time.Time)
+
+		err := scanSegment(path, func(snapshot Snapshot) {
+			row := series{exchange: snapshot.Exchange, kind: snapshot.Kind, pair: snapshot.Pair}
+			times[row] = append(times[row], snapshot.Time)
+		})
+		if err != nil {
+			return time.Time{}, false, err
+		}
+
+		for _, row := range times {
+			sort.Slice(row, func(i, j int) bool {
+				return row[i].Before(row[j])
+			})
+		}
+
+		s.mu.Lock()
+		if _, ok = s.index[path]; !ok {
+			s.index[path] = times
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	times := s.index[path][key]
+	if len(times) == 0 {
+		return time.Time{}, false, nil
+	}
+
+	i := sort.Search(len(times), func(i int) bool {
+		return !times[i].Before(at)
+	})
+
+	switch {
+	case i == 0:
+		return times[0], true, nil
+	case i == len(times):
+		return times[i-1], true, nil
+	case times[i].Sub(at) < at.Sub(times[i-1]):
+		return times[i], true, nil
+	default:
+		return times[i-1], true, nil
+	}
+}
+
+func (s *Store) rotate(start time.Time) error {
+	if err := s.closeSegment(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d%s", start.Format(segmentLayout), time.Now().UnixNano(), segmentExt)
+
+	file, err := os.OpenFile(filepath.Join(s.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	s.file = file
+	s.gz = gzip.NewWriter(file)
+	s.segment = start
+	s.index[file.Name()] = make(map[series][]time.Time)
+
+	return s.cleanup()
+}
+
+func (s *Store) closeSegment() error {
+	if s.gz == nil {
+		return nil
+	}
+
+	err := s.gz.Close()
+
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	s.gz = nil
+	s.file = nil
+
+	return err
+}
+
+func (s *Store) cleanup() error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	segments, err := s.segments()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(-s.retention)
+
+	for _, row := range segments {
+		if row.start.Add(segmentDuration).Before(deadline) {
+			if err = os.Remove(row.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+
+			delete(s.index, row.path)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) segments() ([]segment, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []segment
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+
+		prefix, _, ok := strings.Cut(name, "-")
+		if !ok {
+			continue
+		}
+
+		start, err := time.Parse(segmentLayout, prefix)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, segment{path: filepath.Join(s.dir, name), start: start})
+	}
+
+	return result, nil
+}
+
+func scanSegment(path string, fn func(snapshot Snapshot)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return err
+	}
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(nil, maxLineSize)
+
+	for scanner.Scan() {
+		var snapshot Snapshot
+
+		if err = json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			continue
+		}
+
+		fn(snapshot)
+	}
+
+	if err = scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	return nil
+}
+
+func segmentDistance(row segment, at time.Time) time.Duration {
+	switch end := row.start.Add(segmentDuration); {
+	case at.Before(row.start):
+		return row.start.Sub(at)
+	case !at.Before(end):
+		return at.Sub(end)
+	default:
+		return 0
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
+
+func ParseTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Now().UTC(), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %s", value)
+	}
+
+	if n > 1e12 {
+		return time.UnixMilli(n).UTC(), nil
+	}
+
+	return time.Unix(n, 0).UTC(), nil
+}
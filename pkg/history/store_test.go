@@ -0,0 +1,245 @@
+package history
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSegment(t *testing.T, dir string, start time.Time, snapshots ...Snapshot) string {
+	t.Helper()
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d%s", start.Format(segmentLayout), start.UnixNano(), segmentExt))
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gz := gzip.NewWriter(file)
+
+	for _, snapshot := range snapshots {
+		line, err := json.Marshal(snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = gz.Write(append(line, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func snapshotAt(t time.Time, pairID, data string) Snapshot {
+	return Snapshot{Time: t, Exchange: "fake", Kind: KindOrderBook, Pair: pairID, Data: json.RawMessage(data)}
+}
+
+func TestClosest(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	writeSegment(t, dir, base,
+		snapshotAt(base.Add(time.Minute*10), "BTC-USDT", "1"),
+		snapshotAt(base.Add(time.Minute*50), "BTC-USDT", "2"),
+		snapshotAt(base.Add(time.Minute*55), "ETH-USDT", "9"),
+	)
+	writeSegment(t, dir, base.Add(time.Hour),
+		snapshotAt(base.Add(time.Minute*70), "BTC-USDT", "3"),
+	)
+	writeSegment(t, dir, base.Add(time.Hour*5),
+		snapshotAt(base.Add(time.Hour*5+time.Minute), "BTC-USDT", "4"),
+	)
+
+	store, err := NewStore(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = store.Close()
+	}()
+
+	tests := []struct {
+		name string
+		pair string
+		at   time.Time
+		want string
+		err  error
+	}{
+		{name: "before_all", pair: "BTC-USDT", at: base.Add(-time.Hour), want: "1"},
+		{name: "inside_segment", pair: "BTC-USDT", at: base.Add(time.Minute * 45), want: "2"},
+		{name: "across_segments", pair: "BTC-USDT", at: base.Add(time.Minute * 62), want: "3"},
+		{name: "gap", pair: "BTC-USDT", at: base.Add(time.Hour * 4), want: "4"},
+		{name: "after_all", pair: "BTC-USDT", at: base.Add(time.Hour * 24), want: "4"},
+		{name: "other_pair", pair: "ETH-USDT", at: base.Add(time.Hour * 5), want: "9"},
+		{name: "not_found", pair: "XRP-USDT", at: base, err: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				snapshot, err := store.Closest("fake", KindOrderBook, tt.pair, tt.at)
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+
+				if string(snapshot.Data) != tt.want {
+					t.Errorf("data = %s, want %s", snapshot.Data, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRotate(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = store.Close()
+	}()
+
+	if err = store.Write("fake", KindPairs, "", []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.Write("fake", KindPairs, "", []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, err := store.segments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(segments) != 1 {
+		t.Fatalf("segments = %d, want 1", len(segments))
+	}
+
+	snapshot, err := store.Closest("fake", KindPairs, "", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(snapshot.Data) != `["b"]` {
+		t.Errorf("data = %s, want the latest write", snapshot.Data)
+	}
+
+	store.mu.Lock()
+	store.segment = store.segment.Add(-segmentDuration)
+	store.mu.Unlock()
+
+	if err = store.Write("fake", KindPairs, "", []string{"c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if segments, err = store.segments(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(segments) != 2 {
+		t.Fatalf("segments after rotate = %d, want 2", len(segments))
+	}
+
+	if snapshot, err = store.Closest("fake", KindPairs, "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(snapshot.Data) != `["c"]` {
+		t.Errorf("data = %s, want the write after rotate", snapshot.Data)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(segmentDuration)
+
+	old := writeSegment(t, dir, now.Add(-time.Hour*5), snapshotAt(now.Add(-time.Hour*5), "BTC-USDT", "1"))
+	recent := writeSegment(t, dir, now.Add(-time.Hour), snapshotAt(now.Add(-time.Hour), "BTC-USDT", "2"))
+
+	store, err := NewStore(dir, time.Hour*2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = store.Close()
+	}()
+
+	if _, err = os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expired segment was kept: %v", err)
+	}
+
+	if _, err = os.Stat(recent); err != nil {
+		t.Errorf("recent segment was removed: %v", err)
+	}
+
+	if _, err = store.Closest("fake", KindOrderBook, "BTC-USDT", now); err != nil {
+		t.Fatal(err)
+	}
+
+	store.retention = time.Minute
+
+	if err = store.cleanup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(recent); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("segment was kept after retention shrank: %v", err)
+	}
+
+	if len(store.index) != 0 {
+		t.Errorf("index kept %d removed segments", len(store.index))
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		err   bool
+	}{
+		{value: "2023-09-01T10:00:00Z", want: time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "2023-09-01T12:00:00.5+02:00", want: time.Date(2023, 9, 1, 10, 0, 0, 5e8, time.UTC)},
+		{value: "1693562400", want: time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "1693562400123", want: time.Date(2023, 9, 1, 10, 0, 0, 123e6, time.UTC)},
+		{value: "yesterday", err: true},
+		{value: "2023-09-01", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value)
+		if (err != nil) != tt.err {
+			t.Fatalf("ParseTime(%q) err = %v", tt.value, err)
+		}
+
+		if !tt.err && !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	if got, err := ParseTime(""); err != nil || time.Since(got) > time.Minute {
+		t.Errorf("ParseTime(\"\") = %v, %v; want now", got, err)
+	}
+}
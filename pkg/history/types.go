@@ -0,0 +1,14 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Snapshot struct {
+	Time     time.Time       `json:"time"`
+	Exchange string          `json:"exchange"`
+	Kind     string          `json:"kind"`
+	Pair     string          `json:"pair,omitempty"`
+	Data     json.RawMessage `json:"data"`
+}
@@ -0,0 +1,17 @@
+package logger
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	defaultMaxSize    = 100 << 20
+	defaultMaxBackups = 5
+
+	redacted = "[REDACTED]"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+)
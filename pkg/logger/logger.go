@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type Config struct {
+	File       string
+	MaxSize    int64
+	MaxBackups int
+	Level      string
+	Format     string
+}
+
+func New(cfg Config) (*slog.Logger, io.Closer, error) {
+	var (
+		out    io.Writer = os.Stderr
+		closer io.Closer = nopCloser{}
+	)
+
+	if len(cfg.File) > 0 {
+		file, err := NewRotatingFile(cfg.File, cfg.MaxSize, cfg.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		out, closer = file, file
+	}
+
+	var level slog.Level
+
+	if err := level.UnmarshalText([]byte(cfg.Level)); len(cfg.Level) > 0 && err != nil {
+		_ = closer.Close()
+		return nil, nil, fmt.Errorf("log level: %w", err)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(out, opts)), closer, nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(out, opts)), closer, nil
+	default:
+		_ = closer.Close()
+		return nil, nil, fmt.Errorf("unknown log format: %s", cfg.Format)
+	}
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func NewRequestID() string {
+	buf := make([]byte, 16)
+
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(buf)
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	value, _ := ctx.Value(requestIDKey).(string)
+
+	return value
+}
+
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestID := RequestID(ctx); len(requestID) > 0 {
+		return slog.Default().With("request_id", requestID)
+	}
+
+	return slog.Default()
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	file, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err = file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		path:                "dddddddd\n",
+		backupName(path, 1): "cccccccc\n",
+		backupName(path, 2): "bbbbbbbb\n",
+	}
+
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), data, content)
+		}
+	}
+
+	if _, err = os.Stat(backupName(path, 3)); !os.IsNotExist(err) {
+		t.Errorf("backup beyond max_backups exists: %v", err)
+	}
+}
+
+func TestRotatingFileAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := NewRotatingFile(path, 1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = file.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "old\nnew\n" {
+		t.Errorf("log = %q, want the existing content kept", data)
+	}
+
+	file, err = NewRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = file.Write([]byte(strings.Repeat("x", 20))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ = os.ReadFile(backupName(path, 1)); string(data) != "old\nnew\n" {
+		t.Errorf("backup = %q, want the previous log", data)
+	}
+}
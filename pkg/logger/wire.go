@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	secretHeaders = map[string]bool{
+		"Authorization":        true,
+		"Cookie":               true,
+		"Set-Cookie":           true,
+		"Proxy-Authorization":  true,
+		"X-Api-Key":            true,
+		"X-Bapi-Api-Key":       true,
+		"X-Bapi-Sign":          true,
+		"Ok-Access-Key":        true,
+		"Ok-Access-Sign":       true,
+		"Ok-Access-Passphrase": true,
+		"Key":                  true,
+		"Sign":                 true,
+	}
+
+	secretParams = []string{"key", "sign", "secret", "token", "passphrase", "signature"}
+)
+
+func NewWireLog(exchangeID string, enabled bool, maxBody int) *WireLog {
+	obj := &WireLog{
+		exchangeID: exchangeID,
+		maxBody:    maxBody,
+	}
+
+	obj.enabled.Store(enabled)
+
+	return obj
+}
+
+type WireLog struct {
+	exchangeID string
+	maxBody    int
+	enabled    atomic.Bool
+}
+
+func (w *WireLog) SetEnabled(enabled bool) {
+	w.enabled.Store(enabled)
+}
+
+func (w *WireLog) Enabled() bool {
+	return w.enabled.Load()
+}
+
+func (w *WireLog) Log(ctx context.Context, req *http.Request, rsp *http.Response, body []byte, d time.Duration) {
+	if !w.Enabled() {
+		return
+	}
+
+	FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "upstream",
+		slog.String("exchange", w.exchangeID),
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(req.URL)),
+		slog.Any("request_headers", RedactHeader(req.Header)),
+		slog.Int("status", rsp.StatusCode),
+		slog.Any("response_headers", RedactHeader(rsp.Header)),
+		slog.String("body", Truncate(body, w.maxBody)),
+		slog.Duration("duration", d),
+	)
+}
+
+func RedactHeader(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+
+	for key, values := range header {
+		if secretHeaders[http.CanonicalHeaderKey(key)] {
+			result[key] = redacted
+		} else {
+			result[key] = strings.Join(values, ", ")
+		}
+	}
+
+	return result
+}
+
+func RedactURL(u *url.URL) string {
+	query := u.Query()
+
+	for key := range query {
+		if isSecretParam(key) {
+			query.Set(key, redacted)
+		}
+	}
+
+	result := *u
+	result.User = nil
+	result.RawQuery = query.Encode()
+
+	return result.String()
+}
+
+func Truncate(body []byte, limit int) string {
+	if limit <= 0 || len(body) <= limit {
+		return string(body)
+	}
+
+	return string(body[:limit]) + "...(" + strconv.Itoa(len(body)-limit) + " bytes truncated)"
+}
+
+func isSecretParam(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, row := range secretParams {
+		if strings.Contains(key, row) {
+			return true
+		}
+	}
+
+	return false
+}
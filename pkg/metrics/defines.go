@@ -0,0 +1,78 @@
+package metrics
+
+var (
+	DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+var (
+	Default = NewRegistry()
+
+	httpRequests = Default.NewCounter(
+		"http_requests_total",
+		"HTTP requests served, by route, method and status.",
+		"route", "method", "status",
+	)
+	httpDuration = Default.NewHistogram(
+		"http_request_duration_seconds",
+		"HTTP request latency, by route, method and status.",
+		DefBuckets,
+		"route", "method", "status",
+	)
+	upstreamRequests = Default.NewCounter(
+		"upstream_requests_total",
+		"Requests sent to exchanges, by exchange, endpoint and result code.",
+		"exchange", "endpoint", "code",
+	)
+	upstreamDuration = Default.NewHistogram(
+		"upstream_request_duration_seconds",
+		"Exchange request latency, by exchange and endpoint.",
+		DefBuckets,
+		"exchange", "endpoint",
+	)
+	upstreamErrors = Default.NewCounter(
+		"upstream_errors_total",
+		"Failed exchange requests, by exchange, endpoint and error code.",
+		"exchange", "endpoint", "code",
+	)
+	upstreamLastSuccess = Default.NewGauge(
+		"upstream_last_success_timestamp_seconds",
+		"Unix time of the last successful exchange request.",
+		"exchange",
+	)
+	upstreamSinceSuccess = Default.NewGauge(
+		"upstream_seconds_since_last_success",
+		"Seconds since the last successful exchange request.",
+		"exchange",
+	)
+	rateLimitWait = Default.NewHistogram(
+		"ratelimit_wait_seconds",
+		"Time spent waiting for the exchange rate limiter.",
+		DefBuckets,
+		"exchange",
+	)
+	cacheRequests = Default.NewCounter(
+		"cache_requests_total",
+		"Cache lookups, by cache and result (hit, stale, miss).",
+		"cache", "result",
+	)
+	cacheHitRatio = Default.NewGauge(
+		"cache_hit_ratio",
+		"Share of cache lookups served from cache (hit or stale).",
+		"cache",
+	)
+	qualityFlags = Default.NewCounter(
+		"quality_flags_total",
+		"Market data quality rule violations, by exchange, kind (pair, order_book) and rule.",
+		"exchange", "kind", "rule",
+	)
+	qualityActions = Default.NewCounter(
+		"quality_actions_total",
+		"Snapshots withheld by the quality validator, by exchange, kind and action (rejected, quarantined).",
+		"exchange", "kind", "action",
+	)
+	apiKeyRequests = Default.NewCounter(
+		"api_key_requests_total",
+		"Authenticated requests, by api key and result (allowed, limited, denied, unauthorized).",
+		"key", "result",
+	)
+)
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	CacheHit   = "hit"
+	CacheStale = "stale"
+	CacheMiss  = "miss"
+
+	APIKeyAllowed      = "allowed"
+	APIKeyLimited      = "limited"
+	APIKeyDenied       = "denied"
+	APIKeyUnauthorized = "unauthorized"
+)
+
+var (
+	mu          = new(sync.Mutex)
+	lastSuccess = make(map[string]time.Time)
+	cacheStats  = make(map[string]*[2]float64)
+)
+
+func init() {
+	Default.OnCollect(collect)
+}
+
+func ObserveHTTP(route, method string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+
+	httpRequests.Inc(route, method, code)
+	httpDuration.Observe(d.Seconds(), route, method, code)
+}
+
+func ObserveUpstream(exchangeID, endpoint, code string, d time.Duration) {
+	upstreamRequests.Inc(exchangeID, endpoint, code)
+	upstreamDuration.Observe(d.Seconds(), exchangeID, endpoint)
+
+	if code != "ok" {
+		upstreamErrors.Inc(exchangeID, endpoint, code)
+		return
+	}
+
+	now := time.Now()
+
+	upstreamLastSuccess.Set(float64(now.UnixNano())/1e9, exchangeID)
+
+	mu.Lock()
+	lastSuccess[exchangeID] = now
+	mu.Unlock()
+}
+
+func ObserveRateLimitWait(exchangeID string, d time.Duration) {
+	rateLimitWait.Observe(d.Seconds(), exchangeID)
+}
+
+func ObserveCache(name, result string) {
+	cacheRequests.Inc(name, result)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	stats, ok := cacheStats[name]
+	if !ok {
+		stats = new([2]float64)
+		cacheStats[name] = stats
+	}
+
+	if result != CacheMiss {
+		stats[0]++
+	}
+
+	stats[1]++
+}
+
+func ObserveQuality(exchangeID, kind, rule string) {
+	qualityFlags.Inc(exchangeID, kind, rule)
+}
+
+func ObserveQualityAction(exchangeID, kind, action string) {
+	qualityActions.Inc(exchangeID, kind, action)
+}
+
+func ObserveAPIKey(name, result string) {
+	apiKeyRequests.Inc(name, result)
+}
+
+func collect() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for exchangeID, t := range lastSuccess {
+		upstreamSinceSuccess.Set(time.Since(t).Seconds(), exchangeID)
+	}
+
+	for name, stats := range cacheStats {
+		cacheHitRatio.Set(stats[0]/stats[1], name)
+	}
+}
@@ -0,0 +1,316 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+func NewRegistry() *Registry {
+	return &Registry{
+		mu: new(sync.Mutex),
+	}
+}
+
+type Registry struct {
+	mu         *sync.Mutex
+	collectors []collector
+	hooks      []func()
+}
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+type metric struct {
+	mu     *sync.Mutex
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+type histogramSample struct {
+	labels []string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type Counter struct {
+	metric
+	values map[string]*sample
+}
+
+type Gauge struct {
+	metric
+	values map[string]*sample
+}
+
+type Histogram struct {
+	metric
+	buckets []float64
+	values  map[string]*histogramSample
+}
+
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	obj := &Counter{
+		metric: newMetric(name, help, "counter", labels),
+		values: make(map[string]*sample),
+	}
+
+	r.register(obj)
+
+	return obj
+}
+
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	obj := &Gauge{
+		metric: newMetric(name, help, "gauge", labels),
+		values: make(map[string]*sample),
+	}
+
+	r.register(obj)
+
+	return obj
+}
+
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	obj := &Histogram{
+		metric:  newMetric(name, help, "histogram", labels),
+		buckets: buckets,
+		values:  make(map[string]*histogramSample),
+	}
+
+	r.register(obj)
+
+	return obj
+}
+
+func (r *Registry) OnCollect(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, fn)
+}
+
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	hooks := append([]func(){}, r.hooks...)
+	collectors := append([]collector{}, r.collectors...)
+	r.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+
+	buf := bufio.NewWriter(w)
+
+	for _, row := range collectors {
+		row.write(buf)
+	}
+
+	return buf.Flush()
+}
+
+func (r *Registry) register(obj collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, obj)
+}
+
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+func (c *Counter) Add(value float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.get(labels).value += value
+}
+
+func (c *Counter) get(labels []string) *sample {
+	key := strings.Join(labels, "\xff")
+
+	row, ok := c.values[key]
+	if !ok {
+		row = &sample{labels: append([]string(nil), labels...)}
+		c.values[key] = row
+	}
+
+	return row
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w)
+
+	for _, row := range sortedSamples(c.values) {
+		c.writeSample(w, c.name, row.labels, nil, row.value)
+	}
+}
+
+func (g *Gauge) Set(value float64, labels ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := strings.Join(labels, "\xff")
+
+	row, ok := g.values[key]
+	if !ok {
+		row = &sample{labels: append([]string(nil), labels...)}
+		g.values[key] = row
+	}
+
+	row.value = value
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.writeHeader(w)
+
+	for _, row := range sortedSamples(g.values) {
+		g.writeSample(w, g.name, row.labels, nil, row.value)
+	}
+}
+
+func (h *Histogram) Observe(value float64, labels ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := strings.Join(labels, "\xff")
+
+	row, ok := h.values[key]
+	if !ok {
+		row = &histogramSample{
+			labels: append([]string(nil), labels...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = row
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			row.counts[i]++
+		}
+	}
+
+	row.sum += value
+	row.count++
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w)
+
+	var keys []string
+
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		row := h.values[key]
+
+		for i, bound := range h.buckets {
+			h.writeSample(w, h.name+"_bucket", row.labels, []string{"le", formatFloat(bound)}, float64(row.counts[i]))
+		}
+
+		h.writeSample(w, h.name+"_bucket", row.labels, []string{"le", "+Inf"}, float64(row.count))
+		h.writeSample(w, h.name+"_sum", row.labels, nil, row.sum)
+		h.writeSample(w, h.name+"_count", row.labels, nil, float64(row.count))
+	}
+}
+
+func newMetric(name, help, kind string, labels []string) metric {
+	return metric{
+		mu:     new(sync.Mutex),
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+	}
+}
+
+func (m *metric) writeHeader(w *bufio.Writer) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, escapeHelp(m.help), m.name, m.kind)
+}
+
+func (m *metric) writeSample(w *bufio.Writer, name string, values, extra []string, value float64) {
+	_, _ = w.WriteString(name)
+
+	var pairs []string
+
+	for i, label := range m.labels {
+		if i < len(values) {
+			pairs = append(pairs, label+`="`+escapeLabel(values[i])+`"`)
+		}
+	}
+
+	if len(extra) == 2 {
+		pairs = append(pairs, extra[0]+`="`+extra[1]+`"`)
+	}
+
+	if len(pairs) > 0 {
+		_, _ = w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	_, _ = w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func sortedSamples(values map[string]*sample) []*sample {
+	var keys []string
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]*sample, 0, len(keys))
+
+	for _, key := range keys {
+		result = append(result, values[key])
+	}
+
+	return result
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+
+	counter := r.NewCounter("requests_total", "Requests, by route\nand status \\ code.", "route", "status")
+	counter.Inc("/b", "200")
+	counter.Add(2, "/a", "500")
+	counter.Inc(`/q"x\y`+"\n", "200")
+
+	gauge := r.NewGauge("up", "Up.", "exchange")
+	gauge.Set(1, "okx")
+	gauge.Set(math.Inf(1), "bybit")
+
+	histogram := r.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1}, "exchange")
+	histogram.Observe(0.05, "okx")
+	histogram.Observe(0.5, "okx")
+	histogram.Observe(5, "okx")
+
+	r.NewCounter("empty_total", "Never incremented.")
+
+	collected := 0
+	r.OnCollect(func() {
+		collected++
+	})
+
+	var buf bytes.Buffer
+
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP requests_total Requests, by route\nand status \\ code.
+# TYPE requests_total counter
+requests_total{route="/a",status="500"} 2
+requests_total{route="/b",status="200"} 1
+requests_total{route="/q\"x\\y\n",status="200"} 1
+# HELP up Up.
+# TYPE up gauge
+up{exchange="bybit"} +Inf
+up{exchange="okx"} 1
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{exchange="okx",le="0.1"} 1
+latency_seconds_bucket{exchange="okx",le="1"} 2
+latency_seconds_bucket{exchange="okx",le="+Inf"} 3
+latency_seconds_sum{exchange="okx"} 5.55
+latency_seconds_count{exchange="okx"} 3
+# HELP empty_total Never incremented.
+# TYPE empty_total counter
+`
+
+	if buf.String() != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	if collected != 1 {
+		t.Errorf("collect hooks ran %d times, want 1", collected)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{value: 0, want: "0"},
+		{value: 1.5, want: "1.5"},
+		{value: 1e-9, want: "1e-09"},
+		{value: math.Inf(1), want: "+Inf"},
+		{value: math.Inf(-1), want: "-Inf"},
+		{value: math.NaN(), want: "NaN"},
+	}
+
+	for _, tt := range tests {
+		if got := formatFloat(tt.value); got != tt.want {
+			t.Errorf("formatFloat(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"time"
+)
+
+const (
+	Version = "3.0.3"
+
+	InPath  = "path"
+	InQuery = "query"
+
+	refPrefix = "#/components/schemas/"
+)
+
+var (
+	knownTypes = map[reflect.Type]*Schema{
+		reflect.TypeOf(decimal.Decimal{}):  {Type: "string", Format: "decimal"},
+		reflect.TypeOf(time.Time{}):        {Type: "string", Format: "date-time"},
+		reflect.TypeOf(json.RawMessage{}):  {},
+		reflect.TypeOf((*any)(nil)).Elem(): {},
+	}
+)
@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+}
+
+func (d *Document) AddOperation(method, path string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+
+	item[strings.ToLower(method)] = op
+}
+
+func (d *Document) Schema(t reflect.Type) *Schema {
+	if schema, ok := knownTypes[t]; ok {
+		result := *schema
+		return &result
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		result := d.Schema(t.Elem())
+		result.Nullable = true
+
+		return result
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.Schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.Schema(t.Elem())}
+	case reflect.Struct:
+		if len(t.Name()) == 0 {
+			return d.structSchema(t)
+		}
+
+		if _, ok := d.Components.Schemas[t.Name()]; !ok {
+			d.Components.Schemas[t.Name()] = &Schema{}
+			d.Components.Schemas[t.Name()] = d.structSchema(t)
+		}
+
+		return &Schema{Ref: refPrefix + t.Name()}
+	default:
+		panic(fmt.Sprintf("openapi: unsupported type %s", t))
+	}
+}
+
+func (d *Document) Resolve(schema *Schema) *Schema {
+	for schema != nil && len(schema.Ref) > 0 {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefix)]
+	}
+
+	return schema
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	result := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		result.Properties[name] = d.Schema(field.Type)
+
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			result.Required = append(result.Required, name)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (d *Document) Validate(schema *Schema, data []byte) error {
+	var value any
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return d.validate(schema, value, "$")
+}
+
+func (d *Document) validate(schema *Schema, value any, path string) error {
+	schema = d.Resolve(schema)
+
+	if schema == nil {
+		return fmt.Errorf("%s: unknown schema", path)
+	}
+
+	if value == nil {
+		if schema.Nullable || schema.Type == "array" || schema.Type == "object" || len(schema.Type) == 0 {
+			return nil
+		}
+
+		return fmt.Errorf("%s: null is not %s", path, schema.Type)
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: %T is not string", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %T is not boolean", path, value)
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: %T is not %s", path, value, schema.Type)
+		}
+
+		if schema.Type == "integer" && number != float64(int64(number)) {
+			return fmt.Errorf("%s: %v is not integer", path, number)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %T is not array", path, value)
+		}
+
+		for i, item := range items {
+			if err := d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		return d.validateObject(schema, value, path)
+	default:
+		return fmt.Errorf("%s: unknown type %s", path, schema.Type)
+	}
+
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, value any, path string) error {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: %T is not object", path, value)
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := fields[name]; !ok {
+			return fmt.Errorf("%s: missing required property %s", path, name)
+		}
+	}
+
+	var names []string
+
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+
+		if !ok {
+			property = schema.AdditionalProperties
+		}
+
+		if property == nil {
+			return fmt.Errorf("%s: unexpected property %s", path, name)
+		}
+
+		if err := d.validate(property, fields[name], path+"."+strings.ReplaceAll(name, ".", "\\.")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package quality
+
+import (
+	"exchanges/pkg/exchange"
+	"sync"
+	"time"
+)
+
+func NewValidator(cfg Config) *Validator {
+	obj := &Validator{
+		mu:         new(sync.Mutex),
+		refs:       make(map[string]map[string]reference),
+		assets:     make(map[string]map[string]string),
+		goodPairs:  make(map[string]exchange.Pair),
+		goodBooks:  make(map[string]exchange.OrderBook),
+		quarantine: make(map[string]Quarantined),
+		counts:     make(map[counterKey]uint64),
+		seen:       make(map[string]uint64),
+	}
+
+	obj.SetConfig(cfg)
+
+	return obj
+}
+
+type Config struct {
+	Mode string
+	// MaxAge flags order books whose venue timestamp is older, 0 disables the rule.
+	MaxAge time.Duration
+	// MaxDeviation is the allowed relative distance of a mid price from the median
+	// of the other venues, 0 disables the rule.
+	MaxDeviation float64
+	// MinVenues is the number of other venues needed for a median.
+	MinVenues    int
+	ReferenceTTL time.Duration
+	// Enforce lists the rules that reject or quarantine a snapshot, all rules when empty.
+	Enforce []string
+}
+
+type Validator struct {
+	mu         *sync.Mutex
+	cfg        Config
+	enforce    map[string]bool
+	refs       map[string]map[string]reference
+	assets     map[string]map[string]string
+	goodPairs  map[string]exchange.Pair
+	goodBooks  map[string]exchange.OrderBook
+	quarantine map[string]Quarantined
+	counts     map[counterKey]uint64
+	seen       map[string]uint64
+}
+
+type reference struct {
+	mid float64
+	at  time.Time
+}
+
+type counterKey struct {
+	exchangeID string
+	kind       string
+	rule       string
+}
+
+type Quarantined struct {
+	Exchange  string              `json:"exchange"`
+	Kind      string              `json:"kind"`
+	Pair      string              `json:"pair"`
+	Flags     []string            `json:"flags"`
+	Time      time.Time           `json:"time"`
+	PairValue *exchange.Pair      `json:"pair_value,omitempty"`
+	OrderBook *exchange.OrderBook `json:"order_book,omitempty"`
+}
+
+type Counter struct {
+	Exchange string `json:"exchange"`
+	Kind     string `json:"kind"`
+	Rule     string `json:"rule"`
+	Count    uint64 `json:"count"`
+}
+
+type Report struct {
+	Mode        string        `json:"mode"`
+	Counters    []Counter     `json:"counters"`
+	Quarantined []Quarantined `json:"quarantined"`
+}
@@ -0,0 +1,39 @@
+package quality
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	ModeFlag       = "flag"
+	ModeReject     = "reject"
+	ModeQuarantine = "quarantine"
+
+	RuleNonPositive    = "non_positive"
+	RuleCrossed        = "crossed"
+	RuleLocked         = "locked"
+	RuleNonMonotonic   = "non_monotonic"
+	RuleDuplicateLevel = "duplicate_level"
+	RuleStale          = "stale"
+	RuleDeviation      = "deviation"
+
+	// FlagQuarantined marks the last good snapshot served in place of a quarantined one.
+	FlagQuarantined = "quarantined"
+
+	KindPair      = "pair"
+	KindOrderBook = "order_book"
+
+	ActionRejected    = "rejected"
+	ActionQuarantined = "quarantined"
+
+	defaultMinVenues    = 2
+	defaultReferenceTTL = time.Minute
+)
+
+var (
+	two = decimal.NewFromInt(2)
+
+	Modes = []string{ModeFlag, ModeReject, ModeQuarantine}
+	Rules = []string{RuleNonPositive, RuleCrossed, RuleLocked, RuleNonMonotonic, RuleDuplicateLevel, RuleStale, RuleDeviation}
+)
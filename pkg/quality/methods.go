@@ -0,0 +1,311 @@
+package quality
+
+import (
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/metrics"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (v *Validator) SetConfig(cfg Config) {
+	if len(cfg.Mode) == 0 {
+		cfg.Mode = ModeFlag
+	}
+
+	if cfg.MinVenues <= 0 {
+		cfg.MinVenues = defaultMinVenues
+	}
+
+	if cfg.ReferenceTTL <= 0 {
+		cfg.ReferenceTTL = defaultReferenceTTL
+	}
+
+	enforce := make(map[string]bool)
+
+	for _, rule := range cfg.Enforce {
+		enforce[rule] = true
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.cfg = cfg
+	v.enforce = enforce
+}
+
+// Pairs flags every pair and, depending on the mode, drops or replaces the bad ones.
+// It also learns which pairs of a venue trade the same assets for the cross-venue median.
+func (v *Validator) Pairs(exchangeID string, pairs []exchange.Pair) []exchange.Pair {
+	now := time.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	assets := make(map[string]string, len(pairs))
+	result := make([]exchange.Pair, 0, len(pairs))
+
+	for _, row := range pairs {
+		asset := assetKey(row.BaseAsset, row.QuoteAsset)
+		assets[row.Id] = asset
+
+		flags := checkQuote(row.Bid, row.Ask)
+		mid, _ := row.Bid.Add(row.Ask).Div(two).Float64()
+
+		if len(flags) == 0 && v.deviates(exchangeID, asset, mid, now) {
+			flags = append(flags, RuleDeviation)
+		}
+
+		key := exchangeID + " " + row.Id
+
+		v.count(exchangeID, KindPair, key, pairFingerprint(row), flags)
+
+		if len(flags) == 0 {
+			v.setReference(exchangeID, asset, mid, now)
+			v.goodPairs[key] = row
+			result = append(result, row)
+
+			continue
+		}
+
+		row.Flags = append(slices.Clone(row.Flags), flags...)
+
+		if !v.enforced(flags) {
+			result = append(result, row)
+			continue
+		}
+
+		switch v.cfg.Mode {
+		case ModeReject:
+			metrics.ObserveQualityAction(exchangeID, KindPair, ActionRejected)
+		case ModeQuarantine:
+			metrics.ObserveQualityAction(exchangeID, KindPair, ActionQuarantined)
+
+			bad := row
+			v.quarantine[KindPair+" "+key] = Quarantined{Exchange: exchangeID, Kind: KindPair, Pair: row.Id, Flags: flags, Time: now, PairValue: &bad}
+
+			if good, ok := v.goodPairs[key]; ok {
+				good.Stale = true
+				good.Flags = []string{FlagQuarantined}
+				result = append(result, good)
+			}
+		default:
+			result = append(result, row)
+		}
+	}
+
+	v.assets[exchangeID] = assets
+
+	return result
+}
+
+// OrderBook checks a book in venue order, before it is sorted.
+func (v *Validator) OrderBook(exchangeID, pairID string, book exchange.OrderBook) (exchange.OrderBook, error) {
+	now := time.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	flags, bid, ask := checkBook(book, now, v.cfg.MaxAge)
+	mid, _ := bid.Add(ask).Div(two).Float64()
+
+	asset, ok := v.assets[exchangeID][pairID]
+	if ok && bid.IsPositive() && ask.IsPositive() && checkQuote(bid, ask) == nil && v.deviates(exchangeID, asset, mid, now) {
+		flags = append(flags, RuleDeviation)
+	}
+
+	key := exchangeID + " " + pairID
+
+	v.count(exchangeID, KindOrderBook, key, bookFingerprint(book), flags)
+
+	if len(flags) == 0 {
+		if ok && bid.IsPositive() && ask.IsPositive() {
+			v.setReference(exchangeID, asset, mid, now)
+		}
+
+		v.goodBooks[key] = cloneBook(book)
+		return book, nil
+	}
+
+	book.Flags = append(slices.Clone(book.Flags), flags...)
+
+	if !v.enforced(flags) || v.cfg.Mode == ModeFlag {
+		return book, nil
+	}
+
+	err := exchange.NewError(exchange.ErrBadData, exchangeID, "", fmt.Sprintf("%s order book failed quality checks: %s", pairID, strings.Join(flags, ", ")))
+
+	if v.cfg.Mode == ModeReject {
+		metrics.ObserveQualityAction(exchangeID, KindOrderBook, ActionRejected)
+		return exchange.OrderBook{}, err
+	}
+
+	metrics.ObserveQualityAction(exchangeID, KindOrderBook, ActionQuarantined)
+
+	bad := cloneBook(book)
+	v.quarantine[KindOrderBook+" "+key] = Quarantined{Exchange: exchangeID, Kind: KindOrderBook, Pair: pairID, Flags: flags, Time: now, OrderBook: &bad}
+
+	good, ok := v.goodBooks[key]
+	if !ok {
+		return exchange.OrderBook{}, err
+	}
+
+	good = cloneBook(good)
+	good.Flags = []string{FlagQuarantined}
+
+	return good, nil
+}
+
+func (v *Validator) Report() Report {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	result := Report{
+		Mode:        v.cfg.Mode,
+		Counters:    make([]Counter, 0, len(v.counts)),
+		Quarantined: make([]Quarantined, 0, len(v.quarantine)),
+	}
+
+	for key, count := range v.counts {
+		result.Counters = append(result.Counters, Counter{Exchange: key.exchangeID, Kind: key.kind, Rule: key.rule, Count: count})
+	}
+
+	for _, row := range v.quarantine {
+		result.Quarantined = append(result.Quarantined, row)
+	}
+
+	sort.Slice(result.Counters, func(i, j int) bool {
+		a, b := result.Counters[i], result.Counters[j]
+
+		if a.Exchange != b.Exchange {
+			return a.Exchange < b.Exchange
+		}
+
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		return a.Rule < b.Rule
+	})
+
+	sort.Slice(result.Quarantined, func(i, j int) bool {
+		return result.Quarantined[i].Time.After(result.Quarantined[j].Time)
+	})
+
+	return result
+}
+
+// RemoveExchange forgets the references, good snapshots and quarantine of a venue.
+func (v *Validator) RemoveExchange(exchangeID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.assets, exchangeID)
+
+	for _, venues := range v.refs {
+		delete(venues, exchangeID)
+	}
+
+	prefix := exchangeID + " "
+
+	for key := range v.goodPairs {
+		if strings.HasPrefix(key, prefix) {
+			delete(v.goodPairs, key)
+		}
+	}
+
+	for key := range v.goodBooks {
+		if strings.HasPrefix(key, prefix) {
+			delete(v.goodBooks, key)
+		}
+	}
+
+	for _, kind := range []string{KindPair, KindOrderBook} {
+		for key := range v.seen {
+			if strings.HasPrefix(key, kind+" "+prefix) {
+				delete(v.seen, key)
+			}
+		}
+	}
+
+	for key, row := range v.quarantine {
+		if row.Exchange == exchangeID {
+			delete(v.quarantine, key)
+		}
+	}
+}
+
+// deviates compares the mid price of a venue with the median of the other venues.
+func (v *Validator) deviates(exchangeID, asset string, mid float64, now time.Time) bool {
+	var others []float64
+
+	for venue, ref := range v.refs[asset] {
+		if venue != exchangeID && now.Sub(ref.at) <= v.cfg.ReferenceTTL {
+			others = append(others, ref.mid)
+		}
+	}
+
+	if v.cfg.MaxDeviation <= 0 || len(others) < v.cfg.MinVenues {
+		return false
+	}
+
+	m := median(others)
+
+	return m > 0 && math.Abs(mid-m)/m > v.cfg.MaxDeviation
+}
+
+// setReference records the mid price of a snapshot that passed every rule.
+func (v *Validator) setReference(exchangeID, asset string, mid float64, now time.Time) {
+	venues, ok := v.refs[asset]
+	if !ok {
+		venues = make(map[string]reference)
+		v.refs[asset] = venues
+	}
+
+	venues[exchangeID] = reference{mid: mid, at: now}
+}
+
+func (v *Validator) enforced(flags []string) bool {
+	if len(v.enforce) == 0 {
+		return true
+	}
+
+	for _, rule := range flags {
+		if v.enforce[rule] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// count counts the flags of a snapshot once, however often the same snapshot is served.
+func (v *Validator) count(exchangeID, kind, key string, fingerprint uint64, flags []string) {
+	key = kind + " " + key
+
+	if prev, ok := v.seen[key]; ok && prev == fingerprint {
+		return
+	}
+
+	v.seen[key] = fingerprint
+
+	for _, rule := range flags {
+		v.counts[counterKey{exchangeID: exchangeID, kind: kind, rule: rule}]++
+		metrics.ObserveQuality(exchangeID, kind, rule)
+	}
+}
+
+func assetKey(base, quote string) string {
+	return strings.ToUpper(base) + "/" + strings.ToUpper(quote)
+}
+
+func cloneBook(book exchange.OrderBook) exchange.OrderBook {
+	book.Ask = slices.Clone(book.Ask)
+	book.Bid = slices.Clone(book.Bid)
+	book.Flags = slices.Clone(book.Flags)
+
+	return book
+}
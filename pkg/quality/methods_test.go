@@ -0,0 +1,220 @@
+package quality
+
+import (
+	"errors"
+	"exchanges/pkg/exchange"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func levels(values ...string) [][]decimal.Decimal {
+	var result [][]decimal.Decimal
+
+	for i := 0; i+1 < len(values); i += 2 {
+		result = append(result, []decimal.Decimal{dec(values[i]), dec(values[i+1])})
+	}
+
+	return result
+}
+
+func TestCheckBook(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		book exchange.OrderBook
+		want []string
+	}{
+		{
+			name: "ok",
+			book: exchange.OrderBook{Ask: levels("101", "1", "102", "1"), Bid: levels("100", "1", "99", "1"), Timestamp: now.UnixMilli()},
+		},
+		{
+			name: "crossed",
+			book: exchange.OrderBook{Ask: levels("100", "1"), Bid: levels("101", "1")},
+			want: []string{RuleCrossed},
+		},
+		{
+			name: "locked",
+			book: exchange.OrderBook{Ask: levels("100", "1"), Bid: levels("100", "1")},
+			want: []string{RuleLocked},
+		},
+		{
+			name: "non_positive",
+			book: exchange.OrderBook{Ask: levels("101", "1"), Bid: levels("100", "1", "0", "1")},
+			want: []string{RuleNonPositive},
+		},
+		{
+			name: "duplicate_and_non_monotonic",
+			book: exchange.OrderBook{Ask: levels("101", "1", "101.0", "2"), Bid: levels("99", "1", "100", "1")},
+			want: []string{RuleDuplicateLevel, RuleNonMonotonic},
+		},
+		{
+			name: "stale",
+			book: exchange.OrderBook{Ask: levels("101", "1"), Bid: levels("100", "1"), Timestamp: now.Add(-time.Minute).UnixMilli()},
+			want: []string{RuleStale},
+		},
+		{
+			name: "no_timestamp",
+			book: exchange.OrderBook{Ask: levels("101", "1"), Bid: levels("100", "1")},
+		},
+		{
+			name: "empty",
+			book: exchange.OrderBook{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := checkBook(tt.book, now, time.Second*30)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("checkBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModes(t *testing.T) {
+	good := exchange.OrderBook{Ask: levels("101", "1"), Bid: levels("100", "1")}
+	bad := exchange.OrderBook{Ask: levels("100", "1"), Bid: levels("101", "1")}
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		want      []string
+		wantErr   bool
+		wantQuote decimal.Decimal
+	}{
+		{name: "flag", cfg: Config{Mode: ModeFlag}, want: []string{RuleCrossed}, wantQuote: dec("100")},
+		{name: "reject", cfg: Config{Mode: ModeReject}, wantErr: true},
+		{name: "quarantine", cfg: Config{Mode: ModeQuarantine}, want: []string{FlagQuarantined}, wantQuote: dec("101")},
+		{name: "not_enforced", cfg: Config{Mode: ModeReject, Enforce: []string{RuleStale}}, want: []string{RuleCrossed}, wantQuote: dec("100")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator(tt.cfg)
+
+			if _, err := v.OrderBook("venue", "BTC-USDT", good); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := v.OrderBook("venue", "BTC-USDT", bad)
+
+			if tt.wantErr {
+				if !errors.Is(err, exchange.ErrBadData) {
+					t.Fatalf("error = %v, want %v", err, exchange.ErrBadData)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got.Flags, tt.want) {
+				t.Fatalf("flags = %v, want %v", got.Flags, tt.want)
+			}
+
+			if !got.Ask[0][0].Equal(tt.wantQuote) {
+				t.Fatalf("ask = %v, want %v", got.Ask[0][0], tt.wantQuote)
+			}
+
+			if counts := v.Report().Counters; len(counts) != 1 || counts[0].Rule != RuleCrossed || counts[0].Count != 1 {
+				t.Fatalf("counters = %+v", counts)
+			}
+		})
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	v := NewValidator(Config{MaxDeviation: 0.05})
+
+	pair := func(id, bid, ask string) []exchange.Pair {
+		return []exchange.Pair{{Id: id, BaseAsset: "BTC", QuoteAsset: "USDT", Bid: dec(bid), Ask: dec(ask)}}
+	}
+
+	v.Pairs("a", pair("BTCUSDT", "100", "101"))
+	v.Pairs("b", pair("BTC-USDT", "101", "102"))
+
+	if got := v.Pairs("c", pair("btc_usdt", "150", "151")); !reflect.DeepEqual(got[0].Flags, []string{RuleDeviation}) {
+		t.Fatalf("flags = %v, want %v", got[0].Flags, []string{RuleDeviation})
+	}
+
+	if got := v.Pairs("c", pair("btc_usdt", "102", "103")); got[0].Flags != nil {
+		t.Fatalf("flags = %v, want none", got[0].Flags)
+	}
+
+	book, err := v.OrderBook("c", "btc_usdt", exchange.OrderBook{Ask: levels("91", "1"), Bid: levels("90", "1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(book.Flags, []string{RuleDeviation}) {
+		t.Fatalf("book flags = %v, want %v", book.Flags, []string{RuleDeviation})
+	}
+}
+
+func TestDeviationReference(t *testing.T) {
+	v := NewValidator(Config{MaxDeviation: 0.05})
+
+	pair := func(id, bid, ask string) []exchange.Pair {
+		return []exchange.Pair{{Id: id, BaseAsset: "BTC", QuoteAsset: "USDT", Bid: dec(bid), Ask: dec(ask)}}
+	}
+
+	for _, exchangeID := range []string{"a", "b", "c"} {
+		v.Pairs(exchangeID, pair("BTC-USDT", "100", "101"))
+	}
+
+	for _, exchangeID := range []string{"d", "e"} {
+		if got := v.Pairs(exchangeID, pair("BTC-USDT", "150", "151")); !reflect.DeepEqual(got[0].Flags, []string{RuleDeviation}) {
+			t.Fatalf("%s flags = %v, want %v", exchangeID, got[0].Flags, []string{RuleDeviation})
+		}
+	}
+
+	if got := v.Pairs("a", pair("BTC-USDT", "100", "101")); got[0].Flags != nil {
+		t.Fatalf("flags = %v, want none: a deviating mid was used as a reference", got[0].Flags)
+	}
+}
+
+func TestCountSnapshots(t *testing.T) {
+	v := NewValidator(Config{})
+	bad := exchange.OrderBook{Ask: levels("100", "1"), Bid: levels("101", "1"), Timestamp: 1}
+
+	for i := 0; i < 3; i++ {
+		if _, err := v.OrderBook("venue", "BTC-USDT", bad); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bad.Timestamp = 2
+
+	if _, err := v.OrderBook("venue", "BTC-USDT", bad); err != nil {
+		t.Fatal(err)
+	}
+
+	v.Pairs("venue", []exchange.Pair{{Id: "BTC-USDT", Bid: dec("101"), Ask: dec("100")}})
+	v.Pairs("venue", []exchange.Pair{{Id: "BTC-USDT", Bid: dec("101"), Ask: dec("100")}})
+
+	counts := make(map[string]uint64)
+
+	for _, row := range v.Report().Counters {
+		counts[row.Kind] += row.Count
+	}
+
+	if counts[KindOrderBook] != 2 || counts[KindPair] != 1 {
+		t.Fatalf("counters = %+v, want 2 books and 1 pair", v.Report().Counters)
+	}
+}
@@ -0,0 +1,132 @@
+package quality
+
+import (
+	"exchanges/pkg/exchange"
+	"github.com/shopspring/decimal"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func checkQuote(bid, ask decimal.Decimal) []string {
+	switch {
+	case !bid.IsPositive() || !ask.IsPositive():
+		return []string{RuleNonPositive}
+	case bid.GreaterThan(ask):
+		return []string{RuleCrossed}
+	case bid.Equal(ask):
+		return []string{RuleLocked}
+	default:
+		return nil
+	}
+}
+
+// checkBook runs the per-snapshot rules on a book in venue order and returns the best bid and ask.
+func checkBook(book exchange.OrderBook, now time.Time, maxAge time.Duration) ([]string, decimal.Decimal, decimal.Decimal) {
+	var flags []string
+
+	add := func(rule string) {
+		if !slices.Contains(flags, rule) {
+			flags = append(flags, rule)
+		}
+	}
+
+	bestAsk, askRules := checkSide(book.Ask, func(a, b decimal.Decimal) bool { return a.LessThan(b) })
+	bestBid, bidRules := checkSide(book.Bid, func(a, b decimal.Decimal) bool { return a.GreaterThan(b) })
+
+	for _, rule := range append(askRules, bidRules...) {
+		add(rule)
+	}
+
+	if len(book.Ask) > 0 && len(book.Bid) > 0 && bestBid.IsPositive() && bestAsk.IsPositive() {
+		for _, rule := range checkQuote(bestBid, bestAsk) {
+			add(rule)
+		}
+	}
+
+	if maxAge > 0 && book.Timestamp > 0 && now.Sub(time.UnixMilli(book.Timestamp)) > maxAge {
+		add(RuleStale)
+	}
+
+	sort.Strings(flags)
+
+	return flags, bestBid, bestAsk
+}
+
+// checkSide checks one side, better reports whether a price ranks before another.
+func checkSide(levels [][]decimal.Decimal, better func(a, b decimal.Decimal) bool) (decimal.Decimal, []string) {
+	var (
+		best  decimal.Decimal
+		found bool
+		rules []string
+	)
+
+	seen := make(map[string]bool, len(levels))
+
+	for i, row := range levels {
+		if len(row) < 2 || !row[0].IsPositive() || !row[1].IsPositive() {
+			rules = append(rules, RuleNonPositive)
+			continue
+		}
+
+		if !found || better(row[0], best) {
+			best, found = row[0], true
+		}
+
+		if key := row[0].String(); seen[key] {
+			rules = append(rules, RuleDuplicateLevel)
+		} else {
+			seen[key] = true
+		}
+
+		if i > 0 && len(levels[i-1]) > 0 && better(row[0], levels[i-1][0]) {
+			rules = append(rules, RuleNonMonotonic)
+		}
+	}
+
+	return best, rules
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+
+	n := len(values)
+
+	if n%2 == 1 {
+		return values[n/2]
+	}
+
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+func pairFingerprint(pair exchange.Pair) uint64 {
+	h := fnv.New64a()
+
+	for _, value := range []decimal.Decimal{pair.Bid, pair.Ask, pair.Volume} {
+		_, _ = h.Write([]byte(value.String() + " "))
+	}
+
+	return h.Sum64()
+}
+
+func bookFingerprint(book exchange.OrderBook) uint64 {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte(strconv.FormatInt(book.Timestamp, 10) + " " + strconv.FormatInt(book.Sequence, 10) + " " + strconv.FormatInt(book.UpdateID, 10)))
+
+	for _, side := range [][][]decimal.Decimal{book.Ask, book.Bid} {
+		_, _ = h.Write([]byte("|"))
+
+		for _, row := range side {
+			for _, value := range row {
+				_, _ = h.Write([]byte(" " + value.String()))
+			}
+
+			_, _ = h.Write([]byte(";"))
+		}
+	}
+
+	return h.Sum64()
+}
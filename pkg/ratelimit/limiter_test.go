@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	l := NewLimiter(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("request %d within the burst was rejected", i)
+		}
+	}
+
+	if l.Allow() {
+		t.Error("request over the burst was allowed")
+	}
+
+	if l.Remaining() != 0 {
+		t.Errorf("remaining = %d, want 0", l.Remaining())
+	}
+
+	if delay := l.Delay(); delay <= 0 || delay > time.Second {
+		t.Errorf("delay = %v, want (0, 1s]", delay)
+	}
+
+	l.last = l.last.Add(-time.Second * 2)
+
+	if l.Remaining() != 2 {
+		t.Errorf("remaining after 2s = %d, want 2", l.Remaining())
+	}
+
+	l.last = l.last.Add(-time.Hour)
+
+	if l.Remaining() != 3 {
+		t.Errorf("remaining after refill = %d, want the burst", l.Remaining())
+	}
+}
+
+func TestUnlimited(t *testing.T) {
+	l := NewLimiter(0, 0)
+
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatal("unlimited limiter rejected a request")
+		}
+	}
+
+	if l.Burst() != 1 || l.Remaining() != 1 || l.Delay() != 0 {
+		t.Errorf("burst = %d, remaining = %d, delay = %v", l.Burst(), l.Remaining(), l.Delay())
+	}
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWait(t *testing.T) {
+	l := NewLimiter(100, 1)
+
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Millisecond*15 {
+		t.Errorf("3 requests at 100/s with burst 1 took %v, want at least 20ms", elapsed)
+	}
+}
+
+func TestWaitCancel(t *testing.T) {
+	l := NewLimiter(1, 1)
+
+	if !l.Allow() {
+		t.Fatal("first request was rejected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+
+	if l.tokens < -0.5 {
+		t.Errorf("tokens = %v, cancelled wait kept its reservation", l.tokens)
+	}
+}
@@ -0,0 +1,37 @@
+package render
+
+import "errors"
+
+const (
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatMsgPack = "msgpack"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrNotAcceptable = errors.New("not acceptable")
+
+	Formats = []string{FormatJSON, FormatCSV, FormatNDJSON, FormatMsgPack}
+
+	contentTypes = map[string]string{
+		FormatJSON:    "application/json",
+		FormatCSV:     "text/csv; charset=utf-8",
+		FormatNDJSON:  "application/x-ndjson",
+		FormatMsgPack: "application/msgpack",
+	}
+
+	mediaTypes = []struct {
+		mediaType string
+		format    string
+	}{
+		{"application/json", FormatJSON},
+		{"text/csv", FormatCSV},
+		{"application/x-ndjson", FormatNDJSON},
+		{"application/ndjson", FormatNDJSON},
+		{"application/msgpack", FormatMsgPack},
+		{"application/x-msgpack", FormatMsgPack},
+		{"application/vnd.msgpack", FormatMsgPack},
+	}
+)
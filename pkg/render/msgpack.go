@@ -0,0 +1,140 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+func encodeMsgPack(value any) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var data any
+
+	if err = dec.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err = writeMsgPack(buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeMsgPack(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		return writeMsgPackNumber(buf, v)
+	case string:
+		writeMsgPackString(buf, v)
+	case []any:
+		writeMsgPackHeader(buf, len(v), 0x90, 0xdc, 0xdd)
+
+		for _, row := range v {
+			if err := writeMsgPack(buf, row); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		writeMsgPackHeader(buf, len(v), 0x80, 0xde, 0xdf)
+
+		for _, key := range keys {
+			writeMsgPackString(buf, key)
+
+			if err := writeMsgPack(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: msgpack for %T", ErrUnknownFormat, value)
+	}
+
+	return nil
+}
+
+func writeMsgPackNumber(buf *bytes.Buffer, value json.Number) error {
+	if n, err := value.Int64(); err == nil {
+		switch {
+		case n >= 0 && n <= 0x7f:
+			buf.WriteByte(byte(n))
+		case n < 0 && n >= -32:
+			buf.WriteByte(byte(int8(n)))
+		default:
+			buf.WriteByte(0xd3)
+			_ = binary.Write(buf, binary.BigEndian, n)
+		}
+
+		return nil
+	}
+
+	f, err := value.Float64()
+	if err != nil {
+		return err
+	}
+
+	buf.WriteByte(0xcb)
+	_ = binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+
+	return nil
+}
+
+func writeMsgPackString(buf *bytes.Buffer, value string) {
+	n := len(value)
+
+	switch {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdb)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+
+	buf.WriteString(value)
+}
+
+func writeMsgPackHeader(buf *bytes.Buffer, n int, fix, code16, code32 byte) {
+	switch {
+	case n < 16:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(code16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(code32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
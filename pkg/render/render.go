@@ -0,0 +1,219 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func Negotiate(format string, accepts func(offers ...string) string) (string, error) {
+	if len(format) > 0 {
+		format = strings.ToLower(format)
+
+		if _, ok := contentTypes[format]; !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+		}
+
+		return format, nil
+	}
+
+	offers := make([]string, 0, len(mediaTypes))
+
+	for _, row := range mediaTypes {
+		offers = append(offers, row.mediaType)
+	}
+
+	accepted := accepts(offers...)
+
+	for _, row := range mediaTypes {
+		if row.mediaType == accepted {
+			return row.format, nil
+		}
+	}
+
+	return "", ErrNotAcceptable
+}
+
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+func Encode(format string, value any) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.Marshal(value)
+	case FormatCSV:
+		return encodeCSV(value)
+	case FormatNDJSON:
+		return encodeNDJSON(value)
+	case FormatMsgPack:
+		return encodeMsgPack(value)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+func encodeCSV(value any) ([]byte, error) {
+	header, rows, err := table(value)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+
+	if err = w.Write(header); err != nil {
+		return nil, err
+	}
+
+	if err = w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeNDJSON(value any) ([]byte, error) {
+	var items []any
+
+	switch v := value.(type) {
+	case []string:
+		for _, row := range v {
+			items = append(items, row)
+		}
+	case []exchange.Pair:
+		for _, row := range v {
+			items = append(items, row)
+		}
+	case exchange.OrderBook:
+		for _, row := range bookLevels(v) {
+			items = append(items, row)
+		}
+	case []exchange.Trade:
+		for _, row := range v {
+			items = append(items, row)
+		}
+	default:
+		items = []any{value}
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func table(value any) ([]string, [][]string, error) {
+	switch v := value.(type) {
+	case []string:
+		rows := make([][]string, 0, len(v))
+
+		for _, row := range v {
+			rows = append(rows, []string{row})
+		}
+
+		return []string{"id"}, rows, nil
+	case []exchange.Pair:
+		rows := make([][]string, 0, len(v))
+
+		for _, row := range v {
+			rows = append(rows, []string{
+				row.Id,
+				row.BaseAsset,
+				row.QuoteAsset,
+				row.Ask.String(),
+				row.Bid.String(),
+				row.Volume.String(),
+				strconv.FormatBool(row.Stale),
+				strings.Join(row.Flags, ";"),
+			})
+		}
+
+		return []string{"id", "base_asset", "quote_asset", "ask", "bid", "volume", "stale", "flags"}, rows, nil
+	case exchange.OrderBook:
+		levels := bookLevels(v)
+		rows := make([][]string, 0, len(levels))
+
+		for _, row := range levels {
+			rows = append(rows, []string{row.Side, row.Price, row.Amount, formatID(row.Timestamp), formatID(row.Sequence), formatID(row.UpdateID)})
+		}
+
+		return []string{"side", "price", "amount", "timestamp", "sequence", "update_id"}, rows, nil
+	case []exchange.Trade:
+		rows := make([][]string, 0, len(v))
+
+		for _, row := range v {
+			rows = append(rows, []string{
+				row.Id,
+				row.Time.UTC().Format(time.RFC3339Nano),
+				row.Side,
+				row.Price.String(),
+				row.Amount.String(),
+			})
+		}
+
+		return []string{"id", "time", "side", "price", "amount"}, rows, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: csv for %T", ErrUnknownFormat, value)
+	}
+}
+
+// level is one row of an order book; every row repeats the snapshot's timestamp, sequence and update id.
+type level struct {
+	Side      string `json:"side"`
+	Price     string `json:"price"`
+	Amount    string `json:"amount"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Sequence  int64  `json:"sequence,omitempty"`
+	UpdateID  int64  `json:"update_id,omitempty"`
+}
+
+func bookLevels(book exchange.OrderBook) []level {
+	result := make([]level, 0, len(book.Ask)+len(book.Bid))
+
+	for _, side := range []struct {
+		name string
+		rows [][]decimal.Decimal
+	}{
+		{"ask", book.Ask},
+		{"bid", book.Bid},
+	} {
+		for _, row := range side.rows {
+			if len(row) < 2 {
+				continue
+			}
+
+			result = append(result, level{
+				Side:      side.name,
+				Price:     row[0].String(),
+				Amount:    row[1].String(),
+				Timestamp: book.Timestamp,
+				Sequence:  book.Sequence,
+				UpdateID:  book.UpdateID,
+			})
+		}
+	}
+
+	return result
+}
+
+// formatID leaves unknown ids, which are 0, empty.
+func formatID(value int64) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(value, 10)
+}
@@ -0,0 +1,316 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	testPairs = []exchange.Pair{
+		{Id: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: dec("26751.2"), Bid: dec("26751.19"), Volume: dec("123456789012345678901234.000000001")},
+		{Id: "SHIB-USDT", BaseAsset: "SHIB", QuoteAsset: "USDT", Ask: dec("0.000000012345678901234567"), Bid: dec("0.00000001"), Volume: dec("0"), Stale: true, Flags: []string{"stale", "deviation"}},
+	}
+
+	testBook = exchange.OrderBook{
+		Ask:       [][]decimal.Decimal{{dec("26751.2"), dec("0.000000000000000001")}, {dec("26751.3"), dec("12")}},
+		Bid:       [][]decimal.Decimal{{dec("26751.19"), dec("99999999999999999999.5")}},
+		Timestamp: 1700000000123,
+		Sequence:  42,
+	}
+)
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "ids", value: []string{"bybit", "okx"}, want: []any{"bybit", "okx"}},
+		{name: "pairs", value: testPairs, want: []any{
+			map[string]any{"id": "BTC-USDT", "base_asset": "BTC", "quote_asset": "USDT", "ask": "26751.2", "bid": "26751.19", "volume": "123456789012345678901234.000000001"},
+			map[string]any{"id": "SHIB-USDT", "base_asset": "SHIB", "quote_asset": "USDT", "ask": "0.000000012345678901234567", "bid": "0.00000001", "volume": "0", "stale": true, "flags": []any{"stale", "deviation"}},
+		}},
+		{name: "book", value: testBook, want: map[string]any{
+			"ask":       []any{[]any{"26751.2", "0.000000000000000001"}, []any{"26751.3", "12"}},
+			"bid":       []any{[]any{"26751.19", "99999999999999999999.5"}},
+			"timestamp": int64(1700000000123),
+			"sequence":  int64(42),
+		}},
+		{name: "numbers", value: map[string]any{"small": 5, "negative": -7, "min": int64(math.MinInt64), "max": int64(math.MaxInt64), "float": 0.25, "null": nil}, want: map[string]any{
+			"small": int64(5), "negative": int64(-7), "min": int64(math.MinInt64), "max": int64(math.MaxInt64), "float": 0.25, "null": nil,
+		}},
+		{name: "long", value: []string{string(bytes.Repeat([]byte("a"), 300)), string(bytes.Repeat([]byte("b"), 70000))}, want: []any{
+			string(bytes.Repeat([]byte("a"), 300)), string(bytes.Repeat([]byte("b"), 70000)),
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Encode(FormatMsgPack, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := bytes.NewReader(raw)
+
+			got, err := readMsgPack(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Len() != 0 {
+				t.Errorf("%d trailing bytes", r.Len())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMsgPackSortsKeys(t *testing.T) {
+	a, err := Encode(FormatMsgPack, map[string]int{"b": 1, "a": 2, "c": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []byte{0x83, 0xa1, 'a', 0x02, 0xa1, 'b', 0x01, 0xa1, 'c', 0x03}; !bytes.Equal(a, want) {
+		t.Errorf("got % x, want % x", a, want)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	trades := []exchange.Trade{
+		{Id: "1", Price: dec("26751.123456789012345"), Amount: dec("0.00000001"), Side: "buy", Time: time.Date(2023, 11, 14, 22, 13, 20, 123456789, time.UTC)},
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  [][]string
+	}{
+		{name: "ids", value: []string{"bybit", "okx"}, want: [][]string{{"id"}, {"bybit"}, {"okx"}}},
+		{name: "pairs", value: testPairs, want: [][]string{
+			{"id", "base_asset", "quote_asset", "ask", "bid", "volume", "stale", "flags"},
+			{"BTC-USDT", "BTC", "USDT", "26751.2", "26751.19", "123456789012345678901234.000000001", "false", ""},
+			{"SHIB-USDT", "SHIB", "USDT", "0.000000012345678901234567", "0.00000001", "0", "true", "stale;deviation"},
+		}},
+		{name: "book", value: testBook, want: [][]string{
+			{"side", "price", "amount", "timestamp", "sequence", "update_id"},
+			{"ask", "26751.2", "0.000000000000000001", "1700000000123", "42", ""},
+			{"ask", "26751.3", "12", "1700000000123", "42", ""},
+			{"bid", "26751.19", "99999999999999999999.5", "1700000000123", "42", ""},
+		}},
+		{name: "trades", value: trades, want: [][]string{
+			{"id", "time", "side", "price", "amount"},
+			{"1", "2023-11-14T22:13:20.123456789Z", "buy", "26751.123456789012345", "0.00000001"},
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Encode(FormatCSV, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := csv.NewReader(bytes.NewReader(raw)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Encode(FormatCSV, map[string]string{}); err == nil {
+		t.Error("csv for a map: want error")
+	}
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	raw, err := Encode(FormatNDJSON, testPairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pairs []exchange.Pair
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		var pair exchange.Pair
+
+		if err = json.Unmarshal(scanner.Bytes(), &pair); err != nil {
+			t.Fatal(err)
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	if len(pairs) != len(testPairs) {
+		t.Fatalf("got %d lines, want %d", len(pairs), len(testPairs))
+	}
+
+	for i, pair := range pairs {
+		want := testPairs[i]
+
+		if pair.Id != want.Id || !pair.Ask.Equal(want.Ask) || !pair.Bid.Equal(want.Bid) || !pair.Volume.Equal(want.Volume) || pair.Stale != want.Stale || !reflect.DeepEqual(pair.Flags, want.Flags) {
+			t.Errorf("line %d = %+v, want %+v", i, pair, want)
+		}
+
+		if pair.Volume.String() != want.Volume.String() || pair.Ask.String() != want.Ask.String() {
+			t.Errorf("line %d lost precision: %s %s", i, pair.Ask, pair.Volume)
+		}
+	}
+
+	raw, err = Encode(FormatNDJSON, testBook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"side":"ask","price":"26751.2","amount":"0.000000000000000001","timestamp":1700000000123,"sequence":42}
+{"side":"ask","price":"26751.3","amount":"12","timestamp":1700000000123,"sequence":42}
+{"side":"bid","price":"26751.19","amount":"99999999999999999999.5","timestamp":1700000000123,"sequence":42}
+`
+
+	if string(raw) != want {
+		t.Errorf("got\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func readMsgPack(r *bytes.Reader) (any, error) {
+	code, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return readMsgPackString(r, int(code&0x1f))
+	case code&0xf0 == 0x90:
+		return readMsgPackArray(r, int(code&0x0f))
+	case code&0xf0 == 0x80:
+		return readMsgPackMap(r, int(code&0x0f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xd3:
+		var n int64
+		err = binary.Read(r, binary.BigEndian, &n)
+		return n, err
+	case 0xcb:
+		var n uint64
+		err = binary.Read(r, binary.BigEndian, &n)
+		return math.Float64frombits(n), err
+	case 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf:
+		n, err := readMsgPackLen(r, code)
+		if err != nil {
+			return nil, err
+		}
+
+		switch code {
+		case 0xd9, 0xda, 0xdb:
+			return readMsgPackString(r, n)
+		case 0xdc, 0xdd:
+			return readMsgPackArray(r, n)
+		default:
+			return readMsgPackMap(r, n)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected msgpack code %#x", code)
+	}
+}
+
+func readMsgPackLen(r *bytes.Reader, code byte) (int, error) {
+	switch code {
+	case 0xd9:
+		n, err := r.ReadByte()
+		return int(n), err
+	case 0xda, 0xdc, 0xde:
+		var n uint16
+		err := binary.Read(r, binary.BigEndian, &n)
+		return int(n), err
+	default:
+		var n uint32
+		err := binary.Read(r, binary.BigEndian, &n)
+		return int(n), err
+	}
+}
+
+func readMsgPackString(r *bytes.Reader, n int) (string, error) {
+	buf := make([]byte, n)
+
+	if _, err := r.Read(buf); err != nil && n > 0 {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+func readMsgPackArray(r *bytes.Reader, n int) ([]any, error) {
+	result := make([]any, 0, n)
+
+	for i := 0; i < n; i++ {
+		value, err := readMsgPack(r)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+func readMsgPackMap(r *bytes.Reader, n int) (map[string]any, error) {
+	result := make(map[string]any, n)
+
+	for i := 0; i < n; i++ {
+		key, err := readMsgPack(r)
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack map key %T", key)
+		}
+
+		if result[s], err = readMsgPack(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
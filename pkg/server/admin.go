@@ -0,0 +1,93 @@
+package server
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+func (s *Server) initAdmin(engine *fiber.App) {
+	admin := engine.Group("/admin")
+	scoped := s.adminAuth(scopeAdmin)
+	global := s.adminAuth(scopeAdminGlobal)
+
+	admin.Get("/keys", global, func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(s.KeyUsage())
+	})
+
+	admin.Get("/exchanges", scoped, func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(s.adminStatuses(c))
+	})
+
+	admin.Post("/exchanges/:exchangeID/enable", scoped, func(c *fiber.Ctx) error {
+		return s.adminResult(c, s.SetExchangeEnabled(c.Params("exchangeID"), true))
+	})
+
+	admin.Post("/exchanges/:exchangeID/disable", scoped, func(c *fiber.Ctx) error {
+		return s.adminResult(c, s.SetExchangeEnabled(c.Params("exchangeID"), false))
+	})
+
+	admin.Post("/exchanges/:exchangeID/flush", scoped, func(c *fiber.Ctx) error {
+		return s.adminResult(c, s.FlushCache(c.Params("exchangeID")))
+	})
+
+	admin.Post("/exchanges/:exchangeID/wirelog/enable", scoped, func(c *fiber.Ctx) error {
+		return s.adminResult(c, s.SetWireLog(c.Params("exchangeID"), true))
+	})
+
+	admin.Post("/exchanges/:exchangeID/wirelog/disable", scoped, func(c *fiber.Ctx) error {
+		return s.adminResult(c, s.SetWireLog(c.Params("exchangeID"), false))
+	})
+
+	admin.Get("/quality", global, func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(s.QualityReport())
+	})
+
+	admin.Post("/reload", global, func(c *fiber.Ctx) error {
+		reload := func() func() error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			return s.reload
+		}()
+
+		if reload == nil {
+			return fiber.ErrNotImplemented
+		}
+
+		if err := reload(); err != nil {
+			slog.Error("config reload failed", "error", err)
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+
+		slog.Info("config reloaded")
+
+		return c.Status(fiber.StatusOK).JSON(s.adminStatuses(c))
+	})
+}
+
+func (s *Server) adminResult(c *fiber.Ctx, err error) error {
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(s.adminStatuses(c))
+}
+
+func (s *Server) adminStatuses(c *fiber.Ctx) []ExchangeStatus {
+	obj := clientOf(c)
+	result := s.ExchangeStatuses()
+
+	if obj == nil {
+		return result
+	}
+
+	allowed := result[:0]
+
+	for _, row := range result {
+		if obj.key.ExchangeAllowed(row.Id) {
+			allowed = append(allowed, row)
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"exchanges/pkg/apikey"
+	"exchanges/pkg/metrics"
+	"exchanges/pkg/ratelimit"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+type client struct {
+	key      apikey.Key
+	limiter  *ratelimit.Limiter
+	requests atomic.Uint64
+	limited  atomic.Uint64
+	denied   atomic.Uint64
+	streams  atomic.Int32
+}
+
+func (s *Server) SetAPIKeys(keys []apikey.Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := make(map[string]*client)
+
+	for _, obj := range s.clients {
+		prev[obj.key.Name] = obj
+	}
+
+	clients := make(map[string]*client)
+
+	for _, key := range keys {
+		hash, err := apikey.ParseHash(key.Hash)
+		if err != nil {
+			return fmt.Errorf("api key %s: %w", key.Name, err)
+		}
+
+		if obj, ok := prev[key.Name]; ok && reflect.DeepEqual(obj.key, key) {
+			clients[hash] = obj
+			continue
+		}
+
+		clients[hash] = &client{
+			key:     key,
+			limiter: ratelimit.NewLimiter(key.RateLimit, key.RateBurst),
+		}
+	}
+
+	s.clients = clients
+
+	return nil
+}
+
+func (s *Server) KeyUsage() []KeyUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]KeyUsage, 0, len(s.clients))
+
+	for _, obj := range s.clients {
+		result = append(result, KeyUsage{
+			Name:     obj.key.Name,
+			Requests: obj.requests.Load(),
+			Limited:  obj.limited.Load(),
+			Denied:   obj.denied.Load(),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func (s *Server) auth(c *fiber.Ctx) error {
+	return s.authorize(c, scopeAPI)
+}
+
+func (s *Server) adminAuth(scope int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return s.authorize(c, scope)
+	}
+}
+
+func (s *Server) authorize(c *fiber.Ctx, scope int) error {
+	key := c.Get(apiKeyHeader)
+
+	if len(key) == 0 {
+		if value, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+			key = strings.TrimSpace(value)
+		}
+	}
+
+	obj, enabled := s.getClient(key)
+
+	if !enabled {
+		if scope != scopeAPI && !c.Context().RemoteIP().IsLoopback() {
+			return fiber.NewError(fiber.StatusForbidden, "admin routes need an admin api key in auth.keys or a loopback client")
+		}
+
+		return c.Next()
+	}
+
+	if obj == nil {
+		metrics.ObserveAPIKey("", metrics.APIKeyUnauthorized)
+
+		if len(key) == 0 {
+			return fiber.NewError(fiber.StatusUnauthorized, "missing api key")
+		}
+
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid api key")
+	}
+
+	obj.requests.Add(1)
+
+	if !obj.allowed(c, scope) {
+		obj.denied.Add(1)
+		metrics.ObserveAPIKey(obj.key.Name, metrics.APIKeyDenied)
+
+		return fiber.NewError(fiber.StatusForbidden, "api key is not allowed to use this route")
+	}
+
+	if obj.key.RateLimit > 0 {
+		c.Set(rateLimitLimitHeader, strconv.Itoa(obj.limiter.Burst()))
+
+		if !obj.limiter.Allow() {
+			obj.limited.Add(1)
+			metrics.ObserveAPIKey(obj.key.Name, metrics.APIKeyLimited)
+
+			retryAfter := strconv.Itoa(int(math.Ceil(obj.limiter.Delay().Seconds())))
+
+			c.Set(rateLimitRemainingHeader, "0")
+			c.Set(rateLimitResetHeader, retryAfter)
+			c.Set(fiber.HeaderRetryAfter, retryAfter)
+
+			return fiber.NewError(fiber.StatusTooManyRequests, "api key rate limit exceeded")
+		}
+
+		c.Set(rateLimitRemainingHeader, strconv.Itoa(obj.limiter.Remaining()))
+	}
+
+	metrics.ObserveAPIKey(obj.key.Name, metrics.APIKeyAllowed)
+
+	c.Locals(clientLocal, obj)
+
+	return c.Next()
+}
+
+func (s *Server) getClient(key string) (*client, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.clients) == 0 {
+		return nil, false
+	}
+
+	return s.clients[apikey.Sum(key)], true
+}
+
+func (s *Server) authEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients) > 0
+}
+
+func (c *client) allowed(ctx *fiber.Ctx, scope int) bool {
+	if scope != scopeAPI && (!c.key.Admin || scope == scopeAdminGlobal && len(c.key.Exchanges) > 0) {
+		return false
+	}
+
+	if !c.key.RouteAllowed(ctx.Path()) {
+		return false
+	}
+
+	exchangeID := ctx.Params("exchangeID")
+
+	return len(exchangeID) == 0 || c.key.ExchangeAllowed(exchangeID)
+}
+
+func (c *client) charge() bool {
+	if c.key.RateLimit > 0 && !c.limiter.Allow() {
+		c.limited.Add(1)
+		metrics.ObserveAPIKey(c.key.Name, metrics.APIKeyLimited)
+
+		return false
+	}
+
+	metrics.ObserveAPIKey(c.key.Name, metrics.APIKeyAllowed)
+
+	return true
+}
+
+func clientOf(c *fiber.Ctx) *client {
+	obj, _ := c.Locals(clientLocal).(*client)
+
+	return obj
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"encoding/json"
+	"exchanges/pkg/apikey"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func request(t *testing.T, srv *Server, method, path, key string) (int, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+
+	if len(key) > 0 {
+		req.Header.Set(apiKeyHeader, key)
+	}
+
+	rsp, err := srv.engine.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rsp.StatusCode, body
+}
+
+func newAuthServer(t *testing.T, keys ...apikey.Key) *Server {
+	t.Helper()
+
+	srv := newTestServer(t)
+
+	for i := range keys {
+		keys[i].Hash = "sha256:" + apikey.Sum(keys[i].Name)
+	}
+
+	if err := srv.SetAPIKeys(keys); err != nil {
+		t.Fatal(err)
+	}
+
+	return srv
+}
+
+func TestAuthDisabled(t *testing.T) {
+	srv := newTestServer(t)
+
+	if status, body := request(t, srv, "GET", apiPrefix+"/fake/pairs", ""); status != 200 {
+		t.Errorf("api without keys = %d: %s", status, body)
+	}
+
+	for _, path := range []string{"/admin/exchanges", "/admin/keys", "/admin/exchanges/fake/flush"} {
+		method := "GET"
+		if path == "/admin/exchanges/fake/flush" {
+			method = "POST"
+		}
+
+		if status, _ := request(t, srv, method, path, ""); status != 403 {
+			t.Errorf("%s %s without admin keys = %d, want 403", method, path, status)
+		}
+	}
+}
+
+func TestAuth(t *testing.T) {
+	srv := newAuthServer(t,
+		apikey.Key{Name: "reader"},
+		apikey.Key{Name: "other", Exchanges: []string{"other"}},
+		apikey.Key{Name: "history", Routes: []string{apiPrefix + "/history/"}},
+		apikey.Key{Name: "root", Admin: true},
+		apikey.Key{Name: "ops", Admin: true, Exchanges: []string{"fake"}},
+		apikey.Key{Name: "ops-other", Admin: true, Exchanges: []string{"other"}},
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{name: "missing", method: "GET", path: apiPrefix + "/fake/pairs", status: 401},
+		{name: "invalid", method: "GET", path: apiPrefix + "/fake/pairs", key: "nope", status: 401},
+		{name: "reader", method: "GET", path: apiPrefix + "/fake/pairs", key: "reader", status: 200},
+		{name: "exchange_scope", method: "GET", path: apiPrefix + "/fake/pairs", key: "other", status: 403},
+		{name: "route_scope", method: "GET", path: apiPrefix + "/fake/pairs", key: "history", status: 403},
+		{name: "route_scope_ok", method: "GET", path: apiPrefix + "/history/fake/pairs", key: "history", status: 200},
+		{name: "open_route", method: "GET", path: "/healthz", status: 200},
+		{name: "admin_needs_admin", method: "GET", path: "/admin/exchanges", key: "reader", status: 403},
+		{name: "admin_missing", method: "GET", path: "/admin/exchanges", status: 401},
+		{name: "admin", method: "GET", path: "/admin/keys", key: "root", status: 200},
+		{name: "admin_scoped_global", method: "GET", path: "/admin/keys", key: "ops", status: 403},
+		{name: "admin_scoped_reload", method: "POST", path: "/admin/reload", key: "ops", status: 403},
+		{name: "admin_scoped_exchange", method: "POST", path: "/admin/exchanges/fake/flush", key: "ops", status: 200},
+		{name: "admin_other_exchange", method: "POST", path: "/admin/exchanges/fake/flush", key: "ops-other", status: 403},
+		{name: "admin_other_disable", method: "POST", path: "/admin/exchanges/fake/disable", key: "ops-other", status: 403},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if status, body := request(t, srv, tt.method, tt.path, tt.key); status != tt.status {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, status, tt.status, body)
+			}
+		})
+	}
+
+	_, body := request(t, srv, "GET", "/admin/exchanges", "ops-other")
+
+	var statuses []ExchangeStatus
+
+	if err := json.Unmarshal(body, &statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 0 {
+		t.Errorf("scoped admin sees %v", statuses)
+	}
+}
+
+func TestAuthBearer(t *testing.T) {
+	srv := newAuthServer(t, apikey.Key{Name: "reader"})
+
+	req := httptest.NewRequest("GET", apiPrefix+"/fake/pairs", nil)
+	req.Header.Set("Authorization", "Bearer reader")
+
+	rsp, err := srv.engine.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rsp.StatusCode != 200 {
+		t.Errorf("bearer key = %d", rsp.StatusCode)
+	}
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	srv := newAuthServer(t, apikey.Key{Name: "reader", RateLimit: 0.001, RateBurst: 1})
+
+	if status, _ := request(t, srv, "GET", apiPrefix+"/fake/pairs", "reader"); status != 200 {
+		t.Fatalf("first request = %d", status)
+	}
+
+	req := httptest.NewRequest("GET", apiPrefix+"/fake/pairs", nil)
+	req.Header.Set(apiKeyHeader, "reader")
+
+	rsp, err := srv.engine.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rsp.StatusCode != 429 || rsp.Header.Get("Retry-After") == "" || rsp.Header.Get(rateLimitRemainingHeader) != "0" {
+		t.Errorf("over limit = %d, headers %v", rsp.StatusCode, rsp.Header)
+	}
+
+	usage := srv.KeyUsage()
+
+	if len(usage) != 1 || usage[0].Requests != 2 || usage[0].Limited != 1 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestAdminLoopback(t *testing.T) {
+	srv := newTestServer(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_ = srv.engine.Listener(ln)
+	}()
+
+	defer func() {
+		_ = srv.engine.Shutdown()
+	}()
+
+	for _, route := range []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/admin/exchanges"},
+		{"POST", "/admin/exchanges/fake/flush"},
+		{"POST", "/admin/exchanges/fake/disable"},
+	} {
+		req, err := http.NewRequest(route.method, "http://"+ln.Addr().String()+route.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rsp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = rsp.Body.Close()
+
+		if rsp.StatusCode != 200 {
+			t.Errorf("%s %s from loopback without keys = %d, want 200", route.method, route.path, rsp.StatusCode)
+		}
+	}
+}
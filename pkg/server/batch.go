@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"sync"
+)
+
+type OrderBooksRequest struct {
+	Items []OrderBooksItem `json:"items"`
+}
+
+type OrderBooksItem struct {
+	Exchange string `json:"exchange"`
+	Pair     string `json:"pair"`
+	Depth    int    `json:"depth,omitempty"`
+}
+
+type OrderBooksResponse struct {
+	Results []OrderBooksResult `json:"results"`
+}
+
+type OrderBooksResult struct {
+	Exchange string              `json:"exchange"`
+	Pair     string              `json:"pair"`
+	Status   int                 `json:"status"`
+	Book     *exchange.OrderBook `json:"book,omitempty"`
+	Error    *ErrorResponse      `json:"error,omitempty"`
+}
+
+type bookKey struct {
+	exchangeID string
+	pairID     string
+}
+
+type bookResult struct {
+	book exchange.OrderBook
+	err  error
+}
+
+func (s *Server) getOrderBooks(c *fiber.Ctx) error {
+	var req OrderBooksRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body: "+err.Error())
+	}
+
+	if len(req.Items) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "items must not be empty")
+	}
+
+	if len(req.Items) > maxBatchItems {
+		return fiber.NewError(fiber.StatusBadRequest, "at most "+strconv.Itoa(maxBatchItems)+" items are allowed")
+	}
+
+	obj := clientOf(c)
+
+	keys := make(map[string][]bookKey)
+	seen := make(map[bookKey]bool)
+	limited := make(map[bookKey]bool)
+
+	for _, row := range req.Items {
+		key := bookKey{exchangeID: row.Exchange, pairID: row.Pair}
+
+		if seen[key] || checkBatchItem(obj, row) != nil {
+			continue
+		}
+
+		seen[key] = true
+
+		// The request itself paid for the first book, every other distinct book costs one more token.
+		if len(seen) > 1 && obj != nil && !obj.charge() {
+			limited[key] = true
+			continue
+		}
+
+		keys[row.Exchange] = append(keys[row.Exchange], key)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), s.cfg.ReqTimeout)
+	defer cancel()
+
+	books := s.fetchOrderBooks(ctx, keys)
+
+	rsp := OrderBooksResponse{
+		Results: make([]OrderBooksResult, 0, len(req.Items)),
+	}
+
+	for _, row := range req.Items {
+		result := OrderBooksResult{
+			Exchange: row.Exchange,
+			Pair:     row.Pair,
+		}
+
+		key := bookKey{exchangeID: row.Exchange, pairID: row.Pair}
+
+		err := checkBatchItem(obj, row)
+
+		if err == nil && limited[key] {
+			err = fiber.NewError(fiber.StatusTooManyRequests, "api key rate limit exceeded")
+		}
+
+		if err == nil {
+			value := books[key]
+			book := value.book.Truncate(row.Depth)
+
+			result.Book = &book
+			err = value.err
+		}
+
+		if err != nil {
+			status, errRsp := errorResponse(err)
+
+			if len(errRsp.Exchange) == 0 {
+				errRsp.Exchange = row.Exchange
+			}
+
+			result.Status = status
+			result.Book = nil
+			result.Error = &errRsp
+		} else {
+			result.Status = fiber.StatusOK
+		}
+
+		rsp.Results = append(rsp.Results, result)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rsp)
+}
+
+func checkBatchItem(obj *client, row OrderBooksItem) error {
+	switch {
+	case len(row.Pair) == 0:
+		return fiber.NewError(fiber.StatusBadRequest, "pair must not be empty")
+	case row.Depth < 0:
+		return fiber.NewError(fiber.StatusBadRequest, "depth must not be negative")
+	case obj != nil && !obj.key.ExchangeAllowed(row.Exchange):
+		return fiber.NewError(fiber.StatusForbidden, "api key is not allowed to use this exchange")
+	default:
+		return nil
+	}
+}
+
+func (s *Server) fetchOrderBooks(ctx context.Context, keys map[string][]bookKey) map[bookKey]bookResult {
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+	result := make(map[bookKey]bookResult)
+
+	save := func(key bookKey, book exchange.OrderBook, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		result[key] = bookResult{book: book, err: err}
+	}
+
+	for exchangeID, rows := range keys {
+		obj, err := s.getExchange(exchangeID)
+		if err != nil {
+			for _, key := range rows {
+				save(key, exchange.OrderBook{}, err)
+			}
+
+			continue
+		}
+
+		sem := make(chan struct{}, batchVenueConcurrency)
+
+		for _, key := range rows {
+			wg.Add(1)
+
+			go func(key bookKey) {
+				defer wg.Done()
+
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					save(key, exchange.OrderBook{}, exchange.WrapError(exchange.ErrTimeout, key.exchangeID, ctx.Err()))
+					return
+				}
+
+				defer func() {
+					<-sem
+				}()
+
+				book, err := obj.GetOrderBook(ctx, key.pairID)
+				if err == nil {
+					book, err = s.quality.OrderBook(key.exchangeID, key.pairID, book)
+				}
+
+				if err == nil {
+					book.Sort()
+				}
+
+				save(key, book, err)
+			}(key)
+		}
+	}
+
+	wg.Wait()
+
+	return result
+}
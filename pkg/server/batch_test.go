@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"exchanges/pkg/apikey"
+	"exchanges/pkg/exchange"
+	"github.com/shopspring/decimal"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type batchExchange struct {
+	mu    *sync.Mutex
+	calls map[string]int
+}
+
+func newBatchExchange() *batchExchange {
+	return &batchExchange{
+		mu:    new(sync.Mutex),
+		calls: make(map[string]int),
+	}
+}
+
+func (*batchExchange) GetID() string {
+	return "batch"
+}
+
+func (*batchExchange) GetPairs(context.Context) ([]exchange.Pair, error) {
+	return nil, nil
+}
+
+func (e *batchExchange) GetOrderBook(_ context.Context, pairID string) (exchange.OrderBook, error) {
+	e.mu.Lock()
+	e.calls[pairID]++
+	e.mu.Unlock()
+
+	if pairID == "NONE" {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrPairNotFound, "batch", "404", "pair not found")
+	}
+
+	var book exchange.OrderBook
+
+	for i := int64(0); i < 3; i++ {
+		book.Ask = append(book.Ask, []decimal.Decimal{decimal.NewFromInt(101 + i), decimal.NewFromInt(1)})
+		book.Bid = append(book.Bid, []decimal.Decimal{decimal.NewFromInt(100 - i), decimal.NewFromInt(1)})
+	}
+
+	return book, nil
+}
+
+func (e *batchExchange) callsOf(pairID string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.calls[pairID]
+}
+
+func postBatch(t *testing.T, srv *Server, key, body string) (int, OrderBooksResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", apiPrefix+"/orderbooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(key) > 0 {
+		req.Header.Set(apiKeyHeader, key)
+	}
+
+	rsp, err := srv.engine.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	raw, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result OrderBooksResponse
+
+	if rsp.StatusCode == 200 {
+		if err = json.Unmarshal(raw, &result); err != nil {
+			t.Fatalf("%v: %s", err, raw)
+		}
+	}
+
+	return rsp.StatusCode, result
+}
+
+func checkResults(t *testing.T, got []OrderBooksResult, want []int) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+
+	for i, row := range got {
+		if row.Status != want[i] {
+			t.Errorf("result %d (%s %s) status = %d, want %d: %+v", i, row.Exchange, row.Pair, row.Status, want[i], row.Error)
+		}
+
+		if (row.Book != nil) != (want[i] == 200) || (row.Error != nil) != (want[i] != 200) {
+			t.Errorf("result %d: book %v, error %+v", i, row.Book, row.Error)
+		}
+	}
+}
+
+func TestOrderBooksPartialFailure(t *testing.T) {
+	srv := newTestServer(t)
+	obj := newBatchExchange()
+	srv.SetExchange(obj)
+
+	status, rsp := postBatch(t, srv, "", `{"items": [
+		{"exchange": "batch", "pair": "BTC-USDT", "depth": 2},
+		{"exchange": "batch", "pair": "NONE"},
+		{"exchange": "none", "pair": "BTC-USDT"},
+		{"exchange": "batch", "pair": ""},
+		{"exchange": "batch", "pair": "ETH-USDT", "depth": -1},
+		{"exchange": "fake", "pair": "BTC-USDT"}
+	]}`)
+	if status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+
+	checkResults(t, rsp.Results, []int{200, 404, 404, 400, 400, 200})
+
+	if book := rsp.Results[0].Book; len(book.Ask) != 2 || len(book.Bid) != 2 || !book.Ask[0][0].Equal(decimal.NewFromInt(101)) || !book.Bid[0][0].Equal(decimal.NewFromInt(100)) {
+		t.Errorf("depth 2 book = %+v", book)
+	}
+
+	if code := rsp.Results[1].Error.Code; code != exchange.CodePairNotFound || rsp.Results[1].Error.Exchange != "batch" {
+		t.Errorf("not found error = %+v", rsp.Results[1].Error)
+	}
+
+	if rsp.Results[2].Error.Exchange != "none" {
+		t.Errorf("unknown exchange error = %+v", rsp.Results[2].Error)
+	}
+
+	if n := obj.callsOf("ETH-USDT"); n != 0 {
+		t.Errorf("invalid item fetched %d times", n)
+	}
+}
+
+func TestOrderBooksDuplicates(t *testing.T) {
+	srv := newTestServer(t)
+	obj := newBatchExchange()
+	srv.SetExchange(obj)
+
+	status, rsp := postBatch(t, srv, "", `{"items": [
+		{"exchange": "batch", "pair": "BTC-USDT", "depth": 1},
+		{"exchange": "batch", "pair": "BTC-USDT"},
+		{"exchange": "batch", "pair": "NONE"},
+		{"exchange": "batch", "pair": "NONE"}
+	]}`)
+	if status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+
+	checkResults(t, rsp.Results, []int{200, 200, 404, 404})
+
+	if n := len(rsp.Results[0].Book.Ask); n != 1 {
+		t.Errorf("depth 1 book has %d asks", n)
+	}
+
+	if n := len(rsp.Results[1].Book.Ask); n != 3 {
+		t.Errorf("full duplicate book has %d asks", n)
+	}
+
+	for _, pairID := range []string{"BTC-USDT", "NONE"} {
+		if n := obj.callsOf(pairID); n != 1 {
+			t.Errorf("%s fetched %d times, want 1", pairID, n)
+		}
+	}
+}
+
+func TestOrderBooksLimits(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetExchange(newBatchExchange())
+
+	items := make([]string, 0, maxBatchItems+1)
+
+	for i := 0; i <= maxBatchItems; i++ {
+		items = append(items, `{"exchange": "batch", "pair": "P`+strconv.Itoa(i)+`"}`)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "empty", body: `{"items": []}`, status: 400},
+		{name: "invalid", body: `{"items": `, status: 400},
+		{name: "max", body: `{"items": [` + strings.Join(items[:maxBatchItems], ",") + `]}`, status: 200},
+		{name: "too_many", body: `{"items": [` + strings.Join(items, ",") + `]}`, status: 400},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			status, rsp := postBatch(t, srv, "", tt.body)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+
+			if status == 200 && len(rsp.Results) != maxBatchItems {
+				t.Errorf("got %d results", len(rsp.Results))
+			}
+		})
+	}
+}
+
+func TestOrderBooksQuota(t *testing.T) {
+	srv := newAuthServer(t,
+		apikey.Key{Name: "reader", RateLimit: 0.001, RateBurst: 3},
+		apikey.Key{Name: "scoped", Exchanges: []string{"fake"}},
+	)
+	obj := newBatchExchange()
+	srv.SetExchange(obj)
+
+	status, rsp := postBatch(t, srv, "reader", `{"items": [
+		{"exchange": "batch", "pair": "A"},
+		{"exchange": "batch", "pair": "B"},
+		{"exchange": "batch", "pair": "A", "depth": 1},
+		{"exchange": "batch", "pair": "C"},
+		{"exchange": "batch", "pair": "D"},
+		{"exchange": "batch", "pair": "D"}
+	]}`)
+	if status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+
+	checkResults(t, rsp.Results, []int{200, 200, 200, 200, 429, 429})
+
+	if n := obj.callsOf("D"); n != 0 {
+		t.Errorf("book over quota fetched %d times", n)
+	}
+
+	if status, _ = postBatch(t, srv, "reader", `{"items": [{"exchange": "batch", "pair": "A"}]}`); status != 429 {
+		t.Errorf("next request status = %d, want 429", status)
+	}
+
+	status, rsp = postBatch(t, srv, "scoped", `{"items": [{"exchange": "batch", "pair": "A"}, {"exchange": "fake", "pair": "BTC-USDT"}]}`)
+	if status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+
+	checkResults(t, rsp.Results, []int{403, 200})
+}
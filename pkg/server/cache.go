@@ -0,0 +1,121 @@
+package server
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cachedHeaders = []string{totalCountHeader, nextCursorHeader}
+
+type cachedResponse struct {
+	body        []byte
+	contentType string
+	headers     map[string]string
+	etag        string
+	expires     time.Time
+}
+
+func (s *Server) cached(timeout time.Duration, handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := negotiate(c)
+		if err != nil {
+			return err
+		}
+
+		key := cacheKey(c, format)
+
+		if rsp, ok := s.cacheDB.Get(key).(cachedResponse); ok {
+			return s.sendCached(c, rsp)
+		}
+
+		if err := handler(c); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		sum := sha1.Sum(body)
+
+		headers := make(map[string]string)
+
+		for _, name := range cachedHeaders {
+			if value := c.Response().Header.Peek(name); len(value) > 0 {
+				headers[name] = string(value)
+			}
+		}
+
+		rsp := cachedResponse{
+			body:        body,
+			contentType: string(c.Response().Header.ContentType()),
+			headers:     headers,
+			etag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+			expires:     time.Now().Add(timeout),
+		}
+
+		s.cacheDB.Set(key, timeout, rsp)
+
+		return s.sendCached(c, rsp)
+	}
+}
+
+func cacheKey(c *fiber.Ctx, format string) string {
+	query := make(url.Values)
+
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		query.Add(string(key), string(value))
+	})
+
+	return c.Method() + " " + format + " " + c.Path() + "?" + query.Encode()
+}
+
+// sendCached marks responses private while api keys are on, so shared caches do not hand a key-scoped response to another client.
+func (s *Server) sendCached(c *fiber.Ctx, rsp cachedResponse) error {
+	maxAge := int(time.Until(rsp.expires).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	visibility := "public"
+
+	if s.authEnabled() {
+		visibility = "private"
+		c.Vary(apiKeyHeader, fiber.HeaderAuthorization)
+	}
+
+	c.Set(fiber.HeaderETag, rsp.etag)
+	c.Vary(fiber.HeaderAccept)
+	c.Set(fiber.HeaderCacheControl, visibility+", max-age="+strconv.Itoa(maxAge))
+
+	if etagMatch(c.Get(fiber.HeaderIfNoneMatch), rsp.etag) {
+		c.Response().ResetBody()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	for name, value := range rsp.headers {
+		c.Set(name, value)
+	}
+
+	c.Set(fiber.HeaderContentType, rsp.contentType)
+
+	return c.Status(fiber.StatusOK).Send(rsp.body)
+}
+
+func etagMatch(header, etag string) bool {
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimSpace(value)
+
+		if value == "*" || strings.TrimPrefix(value, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
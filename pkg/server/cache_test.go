@@ -0,0 +1,126 @@
+package server
+
+import (
+	"exchanges/pkg/apikey"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCacheETag(t *testing.T) {
+	srv := newTestServer(t)
+
+	rsp, err := srv.engine.Test(httptest.NewRequest("GET", apiPrefix+"/fake/pairs", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	etag := rsp.Header.Get("ETag")
+	if rsp.StatusCode != 200 || etag == "" {
+		t.Fatalf("status = %d, etag = %q", rsp.StatusCode, etag)
+	}
+
+	if rsp.Header.Get("Cache-Control") == "" {
+		t.Error("Cache-Control is missing")
+	}
+
+	tests := []struct {
+		name   string
+		match  string
+		status int
+	}{
+		{name: "match", match: etag, status: 304},
+		{name: "weak", match: "W/" + etag, status: 304},
+		{name: "list", match: `"other", ` + etag, status: 304},
+		{name: "wildcard", match: "*", status: 304},
+		{name: "mismatch", match: `"other"`, status: 200},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", apiPrefix+"/fake/pairs", nil)
+			req.Header.Set("If-None-Match", tt.match)
+
+			rsp, err := srv.engine.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rsp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", rsp.StatusCode, tt.status)
+			}
+
+			if rsp.Header.Get("ETag") != etag {
+				t.Errorf("etag = %q, want %q", rsp.Header.Get("ETag"), etag)
+			}
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, path := range []string{"/fake/pairs?limit=1&sort=id", "/fake/pairs?sort=id&limit=1"} {
+		status, _, body := get(t, srv, apiPrefix+path)
+		if status != 200 {
+			t.Fatalf("GET %s status = %d: %s", path, status, body)
+		}
+	}
+
+	if n := srv.cacheDB.Len(); n != 1 {
+		t.Errorf("cache entries = %d, want 1 for reordered queries", n)
+	}
+
+	get(t, srv, apiPrefix+"/fake/pairs?limit=2&sort=id")
+
+	if n := srv.cacheDB.Len(); n != 2 {
+		t.Errorf("cache entries = %d, want 2", n)
+	}
+}
+
+func TestCacheControlAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		srv     *Server
+		key     string
+		control string
+		vary    []string
+	}{
+		{name: "open", srv: newTestServer(t), control: "public", vary: []string{"Accept"}},
+		{name: "keys", srv: newAuthServer(t, apikey.Key{Name: "reader"}), key: "reader", control: "private", vary: []string{"Accept", "Authorization", "X-API-Key"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest("GET", apiPrefix+"/fake/pairs", nil)
+
+				if len(tt.key) > 0 {
+					req.Header.Set(apiKeyHeader, tt.key)
+				}
+
+				rsp, err := tt.srv.engine.Test(req, -1)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if control := rsp.Header.Get("Cache-Control"); !strings.HasPrefix(control, tt.control+", max-age=") {
+					t.Errorf("request %d: Cache-Control = %q, want %s", i, control, tt.control)
+				}
+
+				vary := strings.Split(rsp.Header.Get("Vary"), ", ")
+				sort.Strings(vary)
+
+				if !reflect.DeepEqual(vary, tt.vary) {
+					t.Errorf("request %d: Vary = %q, want %q", i, vary, tt.vary)
+				}
+			}
+		})
+	}
+}
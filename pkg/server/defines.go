@@ -5,4 +5,48 @@ import "time"
 const (
 	reqTimeout      = time.Second * 15
 	shutdownTimeout = time.Minute
+
+	exchangesCacheTimeout = time.Minute
+	pairsCacheTimeout     = time.Second * 5
+	orderBookCacheTimeout = time.Second
+	tradesCacheTimeout    = time.Second
+	responseCacheSize     = 10000
+
+	healthProbeAge     = time.Second * 30
+	healthProbeTimeout = time.Second * 5
+
+	grpcMinInterval       = time.Millisecond * 100
+	grpcOrderBookInterval = time.Second
+	grpcTickersInterval   = time.Second * 5
+	grpcMaxStreams        = 10
+
+	apiPrefix  = "/api/v1"
+	apiTitle   = "exchanges"
+	apiVersion = "1.0.0"
+
+	apiKeyHeader             = "X-API-Key"
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+
+	formatLocal = "format"
+	clientLocal = "client"
+
+	maxBatchItems         = 200
+	batchVenueConcurrency = 4
+
+	maxPairsLimit    = 1000
+	totalCountHeader = "X-Total-Count"
+	nextCursorHeader = "X-Next-Cursor"
+
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+const (
+	scopeAPI = iota
+	scopeAdmin
+	scopeAdminGlobal
 )
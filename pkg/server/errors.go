@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"strings"
+)
+
+type ErrorResponse struct {
+	Code         string `json:"code"`
+	Message      string `json:"message"`
+	Exchange     string `json:"exchange,omitempty"`
+	UpstreamCode string `json:"upstream_code,omitempty"`
+}
+
+var (
+	errorStatuses = map[string]int{
+		exchange.CodePairNotFound:        fiber.StatusNotFound,
+		exchange.CodeRateLimited:         fiber.StatusTooManyRequests,
+		exchange.CodeUpstreamUnavailable: fiber.StatusBadGateway,
+		exchange.CodeBadResponse:         fiber.StatusBadGateway,
+		exchange.CodeTimeout:             fiber.StatusGatewayTimeout,
+		exchange.CodeInvalidArgument:     fiber.StatusBadRequest,
+		exchange.CodeCircuitOpen:         fiber.StatusServiceUnavailable,
+		exchange.CodeBadData:             fiber.StatusBadGateway,
+	}
+)
+
+func errorHandler(c *fiber.Ctx, err error) error {
+	code, rsp := errorResponse(err)
+
+	if len(rsp.Exchange) == 0 {
+		rsp.Exchange = c.Params("exchangeID")
+	}
+
+	if code >= fiber.StatusInternalServerError && code != fiber.StatusServiceUnavailable {
+		logger.FromContext(c.UserContext()).Error("request failed", "error", err, "path", c.Path(), "status", code)
+	}
+
+	return c.Status(code).JSON(rsp)
+}
+
+func errorResponse(err error) (int, ErrorResponse) {
+	var e *fiber.Error
+
+	if errors.As(err, &e) {
+		return e.Code, ErrorResponse{
+			Code:    statusCode(e.Code),
+			Message: e.Message,
+		}
+	}
+
+	code := exchange.ErrorCode(err)
+
+	status, ok := errorStatuses[code]
+	if !ok {
+		return fiber.StatusInternalServerError, ErrorResponse{
+			Code:    "internal",
+			Message: "internal server error",
+		}
+	}
+
+	rsp := ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	var exchangeErr *exchange.Error
+
+	if errors.As(err, &exchangeErr) {
+		rsp.Exchange = exchangeErr.Exchange
+		rsp.UpstreamCode = exchangeErr.UpstreamCode
+	}
+
+	return status, rsp
+}
+
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"errors"
+	"exchanges/pkg/render"
+	"github.com/gofiber/fiber/v2"
+)
+
+func negotiate(c *fiber.Ctx) (string, error) {
+	if format, ok := c.Locals(formatLocal).(string); ok {
+		return format, nil
+	}
+
+	format, err := render.Negotiate(c.Query("format"), c.Accepts)
+	if err != nil {
+		if errors.Is(err, render.ErrNotAcceptable) {
+			return "", fiber.NewError(fiber.StatusNotAcceptable, err.Error())
+		}
+
+		return "", fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	c.Locals(formatLocal, format)
+
+	return format, nil
+}
+
+func send(c *fiber.Ctx, value any) error {
+	format, err := negotiate(c)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := render.Encode(format, value)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, render.ContentType(format))
+	c.Vary(fiber.HeaderAccept)
+
+	return c.Status(fiber.StatusOK).Send(rsp)
+}
@@ -0,0 +1,420 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/grpcapi/exchangesv1"
+	"exchanges/pkg/logger"
+	"exchanges/pkg/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	grpcCodes = map[string]codes.Code{
+		exchange.CodePairNotFound:        codes.NotFound,
+		exchange.CodeRateLimited:         codes.ResourceExhausted,
+		exchange.CodeUpstreamUnavailable: codes.Unavailable,
+		exchange.CodeBadResponse:         codes.Unavailable,
+		exchange.CodeTimeout:             codes.DeadlineExceeded,
+		exchange.CodeInvalidArgument:     codes.InvalidArgument,
+		exchange.CodeCircuitOpen:         codes.Unavailable,
+		exchange.CodeBadData:             codes.Unavailable,
+	}
+
+	fiberCodes = map[int]codes.Code{
+		fiber.StatusBadRequest:         codes.InvalidArgument,
+		fiber.StatusNotFound:           codes.NotFound,
+		fiber.StatusServiceUnavailable: codes.Unavailable,
+	}
+)
+
+type grpcService struct {
+	exchangesv1.UnimplementedExchangeServiceServer
+
+	s *Server
+}
+
+type grpcClientKey struct{}
+
+type grpcStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (g *grpcStream) Context() context.Context {
+	return g.ctx
+}
+
+func (s *Server) initGRPC() {
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.grpcUnary),
+		grpc.ChainStreamInterceptor(s.grpcStream),
+	)
+
+	exchangesv1.RegisterExchangeServiceServer(s.grpc, &grpcService{s: s})
+}
+
+func (s *Server) grpcUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	ctx, err := s.grpcAuth(ctx, info.FullMethod)
+	if err == nil {
+		var rsp any
+
+		rsp, err = handler(ctx, req)
+		grpcLog(ctx, info.FullMethod, start, err)
+
+		return rsp, err
+	}
+
+	grpcLog(ctx, info.FullMethod, start, err)
+
+	return nil, err
+}
+
+func (s *Server) grpcStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	ctx, err := s.grpcAuth(stream.Context(), info.FullMethod)
+	if err == nil {
+		err = s.grpcOpenStream(ctx, func() error {
+			return handler(srv, &grpcStream{ServerStream: stream, ctx: ctx})
+		})
+	}
+
+	grpcLog(ctx, info.FullMethod, start, err)
+
+	return err
+}
+
+func (s *Server) grpcOpenStream(ctx context.Context, fn func() error) error {
+	obj, ok := ctx.Value(grpcClientKey{}).(*client)
+	if !ok {
+		return fn()
+	}
+
+	defer obj.streams.Add(-1)
+
+	limit := obj.key.MaxStreams
+	if limit <= 0 {
+		limit = grpcMaxStreams
+	}
+
+	if obj.streams.Add(1) > int32(limit) {
+		obj.limited.Add(1)
+		metrics.ObserveAPIKey(obj.key.Name, metrics.APIKeyLimited)
+
+		return status.Error(codes.ResourceExhausted, "api key has too many open streams")
+	}
+
+	return fn()
+}
+
+func (s *Server) grpcAuth(ctx context.Context, method string) (context.Context, error) {
+	ctx = logger.WithRequestID(ctx, logger.NewRequestID())
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var key string
+
+	if values := md.Get(strings.ToLower(apiKeyHeader)); len(values) > 0 {
+		key = values[0]
+	} else if values = md.Get("authorization"); len(values) > 0 {
+		key = strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+	}
+
+	obj, enabled := s.getClient(key)
+
+	if !enabled {
+		return ctx, nil
+	}
+
+	if obj == nil {
+		metrics.ObserveAPIKey("", metrics.APIKeyUnauthorized)
+		return ctx, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+
+	obj.requests.Add(1)
+
+	if !obj.key.RouteAllowed(method) {
+		obj.denied.Add(1)
+		metrics.ObserveAPIKey(obj.key.Name, metrics.APIKeyDenied)
+
+		return ctx, status.Error(codes.PermissionDenied, "api key is not allowed to use this method")
+	}
+
+	if !obj.charge() {
+		return ctx, status.Error(codes.ResourceExhausted, "api key rate limit exceeded")
+	}
+
+	return context.WithValue(ctx, grpcClientKey{}, obj), nil
+}
+
+func grpcLog(ctx context.Context, method string, start time.Time, err error) {
+	slog.LogAttrs(ctx, slog.LevelInfo, "grpc",
+		slog.String("request_id", logger.RequestID(ctx)),
+		slog.String("method", method),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+func (g *grpcService) ListExchanges(context.Context, *exchangesv1.ListExchangesRequest) (*exchangesv1.ListExchangesResponse, error) {
+	return &exchangesv1.ListExchangesResponse{Exchanges: g.s.enabledExchanges()}, nil
+}
+
+func (g *grpcService) GetPairs(ctx context.Context, req *exchangesv1.GetPairsRequest) (*exchangesv1.GetPairsResponse, error) {
+	pairs, err := g.getPairs(ctx, req.GetExchangeId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &exchangesv1.GetPairsResponse{Pairs: pairs}, nil
+}
+
+func (g *grpcService) GetOrderBook(ctx context.Context, req *exchangesv1.GetOrderBookRequest) (*exchangesv1.OrderBook, error) {
+	rsp, err := g.getOrderBook(ctx, req.GetExchangeId(), req.GetPairId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return rsp, nil
+}
+
+func (g *grpcService) StreamOrderBook(req *exchangesv1.StreamOrderBookRequest, stream exchangesv1.ExchangeService_StreamOrderBookServer) error {
+	var prev *exchangesv1.OrderBook
+
+	return g.poll(stream.Context(), req.GetInterval().AsDuration(), grpcOrderBookInterval, func(ctx context.Context) error {
+		rsp, err := g.getOrderBook(ctx, req.GetExchangeId(), req.GetPairId())
+		if err != nil {
+			return err
+		}
+
+		if prev != nil && proto.Equal(&exchangesv1.OrderBook{Asks: prev.Asks, Bids: prev.Bids}, &exchangesv1.OrderBook{Asks: rsp.Asks, Bids: rsp.Bids}) {
+			return nil
+		}
+
+		prev = rsp
+
+		return stream.Send(rsp)
+	})
+}
+
+func (g *grpcService) StreamTickers(req *exchangesv1.StreamTickersRequest, stream exchangesv1.ExchangeService_StreamTickersServer) error {
+	filter := make(map[string]bool)
+
+	for _, pairID := range req.GetPairIds() {
+		filter[pairID] = true
+	}
+
+	var prev []*exchangesv1.Pair
+
+	return g.poll(stream.Context(), req.GetInterval().AsDuration(), grpcTickersInterval, func(ctx context.Context) error {
+		pairs, err := g.getPairs(ctx, req.GetExchangeId())
+		if err != nil {
+			return err
+		}
+
+		var result []*exchangesv1.Pair
+
+		for _, row := range pairs {
+			if len(filter) == 0 || filter[row.Id] {
+				result = append(result, row)
+			}
+		}
+
+		if prev != nil && proto.Equal(&exchangesv1.Tickers{Pairs: prev}, &exchangesv1.Tickers{Pairs: result}) {
+			return nil
+		}
+
+		prev = result
+
+		return stream.Send(&exchangesv1.Tickers{
+			ExchangeId: req.GetExchangeId(),
+			Pairs:      result,
+			Time:       timestamppb.Now(),
+		})
+	})
+}
+
+func (g *grpcService) poll(ctx context.Context, interval, defaultInterval time.Duration, fn func(ctx context.Context) error) error {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	if interval < grpcMinInterval {
+		interval = grpcMinInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	obj, _ := ctx.Value(grpcClientKey{}).(*client)
+
+	for first := true; ; first = false {
+		if !first && obj != nil && !obj.charge() {
+			return status.Error(codes.ResourceExhausted, "api key rate limit exceeded")
+		}
+
+		if err := fn(ctx); err != nil {
+			if ctx.Err() != nil || !transient(err) {
+				return grpcError(err)
+			}
+
+			logger.FromContext(ctx).Warn("grpc poll failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-g.s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *grpcService) getExchange(ctx context.Context, exchangeID string) (exchange.Exchange, error) {
+	if obj, ok := ctx.Value(grpcClientKey{}).(*client); ok && !obj.key.ExchangeAllowed(exchangeID) {
+		obj.denied.Add(1)
+		metrics.ObserveAPIKey(obj.key.Name, metrics.APIKeyDenied)
+
+		return nil, status.Error(codes.PermissionDenied, "api key is not allowed to use this exchange")
+	}
+
+	obj, err := g.s.getExchange(exchangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (g *grpcService) getPairs(ctx context.Context, exchangeID string) ([]*exchangesv1.Pair, error) {
+	obj, err := g.getExchange(ctx, exchangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.s.cfg.ReqTimeout)
+	defer cancel()
+
+	pairs, err := obj.GetPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs = g.s.quality.Pairs(exchangeID, pairs)
+	exchange.SortPairs(pairs)
+
+	result := make([]*exchangesv1.Pair, 0, len(pairs))
+
+	for _, row := range pairs {
+		result = append(result, &exchangesv1.Pair{
+			Id:         row.Id,
+			BaseAsset:  row.BaseAsset,
+			QuoteAsset: row.QuoteAsset,
+			Ask:        row.Ask.String(),
+			Bid:        row.Bid.String(),
+			Stale:      row.Stale,
+		})
+	}
+
+	return result, nil
+}
+
+func (g *grpcService) getOrderBook(ctx context.Context, exchangeID, pairID string) (*exchangesv1.OrderBook, error) {
+	obj, err := g.getExchange(ctx, exchangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.s.cfg.ReqTimeout)
+	defer cancel()
+
+	book, err := obj.GetOrderBook(ctx, pairID)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err = g.s.quality.OrderBook(exchangeID, pairID, book)
+	if err != nil {
+		return nil, err
+	}
+
+	book.Sort()
+
+	return &exchangesv1.OrderBook{
+		ExchangeId: exchangeID,
+		PairId:     pairID,
+		Asks:       grpcLevels(book.Ask),
+		Bids:       grpcLevels(book.Bid),
+		Time:       timestamppb.Now(),
+		VenueTime:  book.Timestamp,
+		Sequence:   book.Sequence,
+		UpdateId:   book.UpdateID,
+	}, nil
+}
+
+func grpcLevels(rows [][]decimal.Decimal) []*exchangesv1.Level {
+	result := make([]*exchangesv1.Level, 0, len(rows))
+
+	for _, row := range rows {
+		level := new(exchangesv1.Level)
+
+		if len(row) > 0 {
+			level.Price = row[0].String()
+		}
+
+		if len(row) > 1 {
+			level.Amount = row[1].String()
+		}
+
+		result = append(result, level)
+	}
+
+	return result
+}
+
+// transient reports upstream errors that a later poll may not hit, which keep a stream open.
+func transient(err error) bool {
+	return exchange.IsRetryable(err) || errors.Is(err, exchange.ErrCircuitOpen)
+}
+
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var e *fiber.Error
+
+	if errors.As(err, &e) {
+		if code, ok := fiberCodes[e.Code]; ok {
+			return status.Error(code, e.Message)
+		}
+
+		return status.Error(codes.Unknown, e.Message)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	if code, ok := grpcCodes[exchange.ErrorCode(err)]; ok {
+		return status.Error(code, err.Error())
+	}
+
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,309 @@
+package server
+
+import (
+	"context"
+	"exchanges/pkg/apikey"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/grpcapi/exchangesv1"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type tickingExchange struct {
+	calls *atomic.Int64
+}
+
+func (tickingExchange) GetID() string {
+	return "ticking"
+}
+
+func (tickingExchange) GetPairs(context.Context) ([]exchange.Pair, error) {
+	return nil, nil
+}
+
+func (e tickingExchange) GetOrderBook(context.Context, string) (exchange.OrderBook, error) {
+	price := decimal.NewFromInt(100 + e.calls.Add(1))
+
+	return exchange.OrderBook{
+		Ask: [][]decimal.Decimal{{price.Add(decimal.NewFromInt(1)), decimal.NewFromInt(1)}},
+		Bid: [][]decimal.Decimal{{price, decimal.NewFromInt(1)}},
+	}, nil
+}
+
+// flakyExchange fails the calls listed in fail and answers the others like tickingExchange.
+type flakyExchange struct {
+	tickingExchange
+
+	fail map[int64]error
+}
+
+func (e *flakyExchange) GetOrderBook(ctx context.Context, pairID string) (exchange.OrderBook, error) {
+	book, _ := e.tickingExchange.GetOrderBook(ctx, pairID)
+
+	if err, ok := e.fail[e.calls.Load()]; ok {
+		return exchange.OrderBook{}, err
+	}
+
+	return book, nil
+}
+
+func newGRPCClient(t *testing.T, srv *Server) exchangesv1.ExchangeServiceClient {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_ = srv.grpc.Serve(listener)
+	}()
+
+	t.Cleanup(srv.grpc.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return exchangesv1.NewExchangeServiceClient(conn)
+}
+
+func withKey(ctx context.Context, key string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+}
+
+func checkCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Fatalf("code = %s, want %s: %v", got, want, err)
+	}
+}
+
+func TestGRPCService(t *testing.T) {
+	cli := newGRPCClient(t, newTestServer(t))
+	ctx := context.Background()
+
+	list, err := cli.ListExchanges(ctx, &exchangesv1.ListExchangesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.GetExchanges()) != 1 || list.GetExchanges()[0] != "fake" {
+		t.Errorf("exchanges = %v", list.GetExchanges())
+	}
+
+	pairs, err := cli.GetPairs(ctx, &exchangesv1.GetPairsRequest{ExchangeId: "fake"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pairs.GetPairs()) != 2 || pairs.GetPairs()[0].GetId() != "BTC-USDT" || pairs.GetPairs()[0].GetAsk() != "26751.2" {
+		t.Errorf("pairs = %v", pairs.GetPairs())
+	}
+
+	book, err := cli.GetOrderBook(ctx, &exchangesv1.GetOrderBookRequest{ExchangeId: "fake", PairId: "BTC-USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(book.GetAsks()) != 1 || book.GetAsks()[0].GetPrice() != "26751.2" || book.GetBids()[0].GetAmount() != "0.7" {
+		t.Errorf("book = %v", book)
+	}
+
+	if book.GetSequence() != 7 || book.GetUpdateId() != 8 || book.GetVenueTime() != 0 {
+		t.Errorf("book sequence = %d, update id = %d, venue time = %d", book.GetSequence(), book.GetUpdateId(), book.GetVenueTime())
+	}
+
+	_, err = cli.GetOrderBook(ctx, &exchangesv1.GetOrderBookRequest{ExchangeId: "fake", PairId: "NONE"})
+	checkCode(t, err, codes.NotFound)
+
+	_, err = cli.GetPairs(ctx, &exchangesv1.GetPairsRequest{ExchangeId: "none"})
+	checkCode(t, err, codes.NotFound)
+}
+
+func TestGRPCAuth(t *testing.T) {
+	srv := newAuthServer(t,
+		apikey.Key{Name: "reader"},
+		apikey.Key{Name: "http", Routes: []string{apiPrefix + "/"}},
+		apikey.Key{Name: "unary", Routes: []string{"/exchanges.v1.ExchangeService/Get"}},
+		apikey.Key{Name: "other", Exchanges: []string{"other"}},
+	)
+	cli := newGRPCClient(t, srv)
+	req := &exchangesv1.GetPairsRequest{ExchangeId: "fake"}
+
+	tests := []struct {
+		name string
+		key  string
+		code codes.Code
+	}{
+		{name: "missing", code: codes.Unauthenticated},
+		{name: "invalid", key: "nope", code: codes.Unauthenticated},
+		{name: "reader", key: "reader", code: codes.OK},
+		{name: "http_routes", key: "http", code: codes.PermissionDenied},
+		{name: "method_routes", key: "unary", code: codes.OK},
+		{name: "exchange_scope", key: "other", code: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if len(tt.key) > 0 {
+				ctx = withKey(ctx, tt.key)
+			}
+
+			_, err := cli.GetPairs(ctx, req)
+			checkCode(t, err, tt.code)
+		})
+	}
+
+	stream, err := cli.StreamOrderBook(withKey(context.Background(), "unary"), &exchangesv1.StreamOrderBookRequest{ExchangeId: "fake", PairId: "BTC-USDT"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+
+	checkCode(t, err, codes.PermissionDenied)
+}
+
+func TestGRPCStreamDedup(t *testing.T) {
+	cli := newGRPCClient(t, newTestServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	defer cancel()
+
+	stream, err := cli.StreamOrderBook(ctx, &exchangesv1.StreamOrderBookRequest{ExchangeId: "fake", PairId: "BTC-USDT", Interval: durationpb.New(grpcMinInterval)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = stream.Recv()
+	checkCode(t, err, codes.DeadlineExceeded)
+}
+
+func TestGRPCStreamRateLimit(t *testing.T) {
+	srv := newAuthServer(t, apikey.Key{Name: "reader", RateLimit: 0.001, RateBurst: 3})
+	obj := tickingExchange{calls: new(atomic.Int64)}
+	srv.SetExchange(obj)
+
+	cli := newGRPCClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(withKey(context.Background(), "reader"), time.Millisecond*700)
+	defer cancel()
+
+	stream, err := cli.StreamOrderBook(ctx, &exchangesv1.StreamOrderBookRequest{ExchangeId: "ticking", PairId: "BTC-USDT", Interval: durationpb.New(grpcMinInterval)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := 0
+
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+
+		received++
+	}
+
+	checkCode(t, err, codes.ResourceExhausted)
+
+	if received != 3 || obj.calls.Load() != 3 {
+		t.Errorf("received %d books from %d upstream calls, want 3 within the burst", received, obj.calls.Load())
+	}
+}
+
+func TestGRPCStreamTransientError(t *testing.T) {
+	srv := newTestServer(t)
+	obj := &flakyExchange{tickingExchange: tickingExchange{calls: new(atomic.Int64)}, fail: map[int64]error{
+		1: exchange.NewError(exchange.ErrUpstreamUnavailable, "ticking", "", "down"),
+		2: exchange.NewError(exchange.ErrTimeout, "ticking", "", "timeout"),
+		4: exchange.NewError(exchange.ErrPairNotFound, "ticking", "", "pair not found"),
+	}}
+	srv.SetExchange(obj)
+
+	cli := newGRPCClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	stream, err := cli.StreamOrderBook(ctx, &exchangesv1.StreamOrderBookRequest{ExchangeId: "ticking", PairId: "BTC-USDT", Interval: durationpb.New(grpcMinInterval)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = stream.Recv(); err != nil {
+		t.Fatalf("stream ended on a transient error: %v", err)
+	}
+
+	_, err = stream.Recv()
+	checkCode(t, err, codes.NotFound)
+
+	if calls := obj.calls.Load(); calls != 4 {
+		t.Errorf("upstream calls = %d, want 4", calls)
+	}
+}
+
+func TestGRPCStreamLimit(t *testing.T) {
+	srv := newAuthServer(t, apikey.Key{Name: "reader", MaxStreams: 1})
+	cli := newGRPCClient(t, srv)
+
+	ctx, cancel := context.WithCancel(withKey(context.Background(), "reader"))
+	defer cancel()
+
+	req := &exchangesv1.StreamOrderBookRequest{ExchangeId: "fake", PairId: "BTC-USDT"}
+
+	first, err := cli.StreamOrderBook(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = first.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := cli.StreamOrderBook(ctx, req)
+	if err == nil {
+		_, err = second.Recv()
+	}
+
+	checkCode(t, err, codes.ResourceExhausted)
+}
+
+func TestGRPCShutdown(t *testing.T) {
+	srv := newTestServer(t)
+	cli := newGRPCClient(t, srv)
+
+	stream, err := cli.StreamOrderBook(context.Background(), &exchangesv1.StreamOrderBookRequest{ExchangeId: "fake", PairId: "BTC-USDT", Interval: durationpb.New(grpcMinInterval)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	close(srv.done)
+
+	_, err = stream.Recv()
+	checkCode(t, err, codes.Unavailable)
+}
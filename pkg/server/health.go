@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"exchanges/pkg/exchange"
+	"github.com/gofiber/fiber/v2"
+	"sync"
+	"time"
+)
+
+func (s *Server) initHealth(engine *fiber.App) {
+	engine.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok"})
+	})
+
+	engine.Get("/readyz", func(c *fiber.Ctx) error {
+		rsp := s.Readiness(c.Context())
+
+		status := fiber.StatusOK
+		if !rsp.Ready {
+			status = fiber.StatusServiceUnavailable
+		}
+
+		return c.Status(status).JSON(rsp)
+	})
+}
+
+func (s *Server) Readiness(ctx context.Context) Readiness {
+	objs := func() map[string]exchange.Exchange {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		result := make(map[string]exchange.Exchange)
+
+		for exchangeID, row := range s.exchanges {
+			if !row.disabled {
+				result[exchangeID] = row.obj
+			}
+		}
+
+		return result
+	}()
+
+	result := Readiness{
+		Exchanges: make(map[string]exchange.Health),
+	}
+
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+
+	for exchangeID, obj := range objs {
+		wg.Add(1)
+
+		go func(exchangeID string, obj exchange.Exchange) {
+			defer wg.Done()
+
+			health := probe(ctx, obj)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			result.Exchanges[exchangeID] = health
+		}(exchangeID, obj)
+	}
+
+	wg.Wait()
+
+	var down int
+
+	for _, health := range result.Exchanges {
+		if health.Status == exchange.HealthDown {
+			down++
+		}
+	}
+
+	if s.cfg.ReadyRequireAll {
+		result.Ready = len(objs) > 0 && down == 0
+	} else {
+		result.Ready = len(objs) > down
+	}
+
+	return result
+}
+
+func probe(ctx context.Context, obj exchange.Exchange) exchange.Health {
+	reporter, ok := obj.(exchange.HealthReporter)
+	if !ok {
+		return exchange.Health{Status: exchange.HealthUnknown}
+	}
+
+	health := reporter.Health()
+
+	pinger, ok := obj.(exchange.Pinger)
+	if !ok {
+		return health
+	}
+
+	if health.Status != exchange.HealthUnknown && time.Since(health.LastActivity()) < healthProbeAge {
+		return health
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	_ = pinger.Ping(ctx)
+
+	return reporter.Health()
+}
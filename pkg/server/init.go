@@ -2,122 +2,300 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"exchanges/pkg/exchange"
+	"exchanges/pkg/history"
+	"exchanges/pkg/openapi"
+	"exchanges/pkg/render"
 	"github.com/gofiber/fiber/v2"
-	"log"
-	"sort"
+	"reflect"
+	"strconv"
 )
 
+type route struct {
+	method      string
+	path        string
+	operationID string
+	summary     string
+	query       []openapi.Parameter
+	request     reflect.Type
+	response    reflect.Type
+	formats     bool
+	v1Only      bool
+	handler     fiber.Handler
+	// legacy serves the deprecated unprefixed route, handler when nil.
+	legacy fiber.Handler
+}
+
 func (s *Server) init() {
 	cfg := fiber.Config{
 		DisableStartupMessage: true,
 		StrictRouting:         true,
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
+		ErrorHandler:          errorHandler,
+	}
+
+	engine := fiber.New(cfg)
+
+	s.initMiddleware(engine)
+	s.initMetrics(engine)
+	s.initHealth(engine)
+	s.initAdmin(engine)
+
+	routes := s.routes()
+
+	api := engine.Group(apiPrefix)
+
+	api.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(s.openAPI(routes))
+	})
 
-			var e *fiber.Error
+	for _, row := range routes {
+		api.Add(row.method, row.path, s.auth, row.handler)
+	}
+
+	for _, row := range routes {
+		if row.v1Only {
+			continue
+		}
+
+		handler := row.handler
+		if row.legacy != nil {
+			handler = row.legacy
+		}
 
-			if errors.As(err, &e) {
-				code = e.Code
-			}
+		engine.Add(row.method, row.path, deprecated, s.auth, handler)
+	}
 
-			if code == fiber.StatusInternalServerError {
-				log.Printf("%v [path: %s]", err, c.Path())
-			}
+	s.engine = engine
+}
+
+func (s *Server) routes() []route {
+	atParam := queryParam("at", "RFC3339 time or unix seconds/milliseconds, now when empty")
+
+	pairsParams := []openapi.Parameter{
+		queryParam("base", "Base asset"),
+		queryParam("quote", "Quote asset"),
+		queryParam("prefix", "Pair id prefix"),
+		queryParam("q", "Search term matched against id, base and quote"),
+		queryParam("sort", "id, spread, volume or price, id when empty"),
+		queryParam("order", "asc or desc, asc when empty"),
+		queryParam("limit", "Page size, up to "+strconv.Itoa(maxPairsLimit)+", all pairs when empty"),
+		queryParam("cursor", "next_cursor of the previous page, with the same filters and sort"),
+	}
 
-			return c.Status(code).Send(nil)
+	return []route{
+		{
+			method:      fiber.MethodGet,
+			path:        "/exchanges",
+			operationID: "listExchanges",
+			summary:     "List enabled exchanges",
+			response:    reflect.TypeOf([]string{}),
+			formats:     true,
+			handler:     s.cached(exchangesCacheTimeout, s.getExchanges),
+		},
+		{
+			method:      fiber.MethodGet,
+			path:        "/history/:exchangeID/pairs",
+			operationID: "getPairsHistory",
+			summary:     "Pairs snapshot closest to a point in time",
+			query:       []openapi.Parameter{atParam},
+			response:    reflect.TypeOf(history.Snapshot{}),
+			handler: func(c *fiber.Ctx) error {
+				return s.sendHistory(c, history.KindPairs, "")
+			},
+		},
+		{
+			method:      fiber.MethodGet,
+			path:        "/history/:exchangeID/orderbook/:pairID",
+			operationID: "getOrderBookHistory",
+			summary:     "Order book snapshot closest to a point in time",
+			query:       []openapi.Parameter{atParam},
+			response:    reflect.TypeOf(history.Snapshot{}),
+			handler: func(c *fiber.Ctx) error {
+				return s.sendHistory(c, history.KindOrderBook, c.Params("pairID"))
+			},
+		},
+		{
+			method:      fiber.MethodGet,
+			path:        "/:exchangeID/pairs",
+			operationID: "getPairs",
+			summary:     "Spot pairs with best ask and bid",
+			query:       pairsParams,
+			response:    reflect.TypeOf(PairsResponse{}),
+			formats:     true,
+			handler:     s.cached(pairsCacheTimeout, s.getPairs),
+			legacy:      s.cached(pairsCacheTimeout, s.getPairsList),
+		},
+		{
+			method:      fiber.MethodGet,
+			path:        "/:exchangeID/orderbook/:pairID",
+			operationID: "getOrderBook",
+			summary:     "Order book of a pair",
+			response:    reflect.TypeOf(exchange.OrderBook{}),
+			formats:     true,
+			handler:     s.cached(orderBookCacheTimeout, s.getOrderBook),
+		},
+		{
+			method:      fiber.MethodGet,
+			path:        "/:exchangeID/trades/:pairID",
+			operationID: "getTrades",
+			summary:     "Recent trades of a pair, newest first",
+			response:    reflect.TypeOf([]exchange.Trade{}),
+			formats:     true,
+			v1Only:      true,
+			handler:     s.cached(tradesCacheTimeout, s.getTrades),
+		},
+		{
+			method:      fiber.MethodPost,
+			path:        "/orderbooks",
+			operationID: "getOrderBooks",
+			summary:     "Order books of many pairs in one request",
+			request:     reflect.TypeOf(OrderBooksRequest{}),
+			response:    reflect.TypeOf(OrderBooksResponse{}),
+			v1Only:      true,
+			handler:     s.getOrderBooks,
 		},
 	}
+}
 
-	engine := fiber.New(cfg)
+func deprecated(c *fiber.Ctx) error {
+	c.Set("Deprecation", "true")
+	c.Set("Link", "<"+apiPrefix+c.OriginalURL()+`>; rel="successor-version"`)
 
-	engine.Get("/exchanges", func(c *fiber.Ctx) error {
-		s.mu.Lock()
-		defer s.mu.Unlock()
+	return c.Next()
+}
 
-		var list []string
+func (s *Server) getExchanges(c *fiber.Ctx) error {
+	return send(c, s.enabledExchanges())
+}
 
-		for exchangeID := range s.exchanges {
-			list = append(list, exchangeID)
-		}
+func (s *Server) getPairs(c *fiber.Ctx) error {
+	rsp, err := s.pairsPage(c)
+	if err != nil {
+		return err
+	}
 
-		sort.Slice(list, func(i, j int) bool {
-			return list[i] < list[j]
-		})
+	format, err := negotiate(c)
+	if err != nil {
+		return err
+	}
 
-		rsp, err := json.Marshal(list)
-		if err != nil {
-			return err
-		}
+	if format == render.FormatCSV || format == render.FormatNDJSON {
+		return send(c, rsp.Pairs)
+	}
 
-		c.Set("Content-Type", "application/json")
+	return send(c, rsp)
+}
 
-		return c.Status(fiber.StatusOK).Send(rsp)
-	})
+func (s *Server) getPairsList(c *fiber.Ctx) error {
+	rsp, err := s.pairsPage(c)
+	if err != nil {
+		return err
+	}
 
-	engine.Get("/:exchangeID/pairs", func(c *fiber.Ctx) error {
-		obj := func() exchange.Exchange {
-			s.mu.Lock()
-			defer s.mu.Unlock()
+	return send(c, rsp.Pairs)
+}
 
-			obj, _ := s.exchanges[c.Params("exchangeID")]
+func (s *Server) pairsPage(c *fiber.Ctx) (PairsResponse, error) {
+	query, err := parsePairsQuery(c)
+	if err != nil {
+		return PairsResponse{}, err
+	}
 
-			return obj
-		}()
+	obj, err := s.getExchange(c.Params("exchangeID"))
+	if err != nil {
+		return PairsResponse{}, err
+	}
 
-		if obj == nil {
-			return fiber.ErrNotFound
-		}
+	ctx, cancel := context.WithTimeout(c.UserContext(), s.cfg.ReqTimeout)
+	defer cancel()
 
-		ctx, cancel := context.WithTimeout(context.Background(), reqTimeout)
-		defer cancel()
+	pairs, err := obj.GetPairs(ctx)
+	if err != nil {
+		return PairsResponse{}, err
+	}
 
-		rsp, err := obj.GetPairs(ctx)
-		if err != nil {
-			return err
-		}
+	rsp := query.apply(s.quality.Pairs(obj.GetID(), pairs))
 
-		sort.Slice(rsp, func(i, j int) bool {
-			return rsp[i].Id < rsp[j].Id
-		})
+	c.Set(totalCountHeader, strconv.Itoa(rsp.Total))
+	if len(rsp.NextCursor) > 0 {
+		c.Set(nextCursorHeader, rsp.NextCursor)
+	}
 
-		return c.Status(fiber.StatusOK).JSON(rsp)
-	})
+	return rsp, nil
+}
 
-	engine.Get("/:exchangeID/orderbook/:pairID", func(c *fiber.Ctx) error {
-		obj := func() exchange.Exchange {
-			s.mu.Lock()
-			defer s.mu.Unlock()
+func (s *Server) getOrderBook(c *fiber.Ctx) error {
+	obj, err := s.getExchange(c.Params("exchangeID"))
+	if err != nil {
+		return err
+	}
 
-			obj, _ := s.exchanges[c.Params("exchangeID")]
+	ctx, cancel := context.WithTimeout(c.UserContext(), s.cfg.ReqTimeout)
+	defer cancel()
 
-			return obj
-		}()
+	rsp, err := obj.GetOrderBook(ctx, c.Params("pairID"))
+	if err != nil {
+		return err
+	}
 
-		if obj == nil {
-			return fiber.ErrNotFound
-		}
+	rsp, err = s.quality.OrderBook(obj.GetID(), c.Params("pairID"), rsp)
+	if err != nil {
+		return err
+	}
 
-		ctx, cancel := context.WithTimeout(context.Background(), reqTimeout)
-		defer cancel()
+	rsp.Sort()
 
-		rsp, err := obj.GetOrderBook(ctx, c.Params("pairID"))
-		if err != nil {
-			return err
-		}
+	return send(c, rsp)
+}
 
-		sort.Slice(rsp.Ask, func(i, j int) bool {
-			return rsp.Ask[i][0].LessThan(rsp.Ask[j][0])
-		})
+func (s *Server) getTrades(c *fiber.Ctx) error {
+	obj, err := s.getExchange(c.Params("exchangeID"))
+	if err != nil {
+		return err
+	}
 
-		sort.Slice(rsp.Bid, func(i, j int) bool {
-			return rsp.Bid[i][0].GreaterThan(rsp.Bid[j][0])
-		})
+	lister, ok := obj.(exchange.TradeLister)
+	if !ok {
+		return fiber.NewError(fiber.StatusNotImplemented, "exchange does not provide trades")
+	}
 
-		return c.Status(fiber.StatusOK).JSON(rsp)
-	})
+	ctx, cancel := context.WithTimeout(c.UserContext(), s.cfg.ReqTimeout)
+	defer cancel()
 
-	s.engine = engine
+	rsp, err := lister.GetTrades(ctx, c.Params("pairID"))
+	if err != nil {
+		return err
+	}
+
+	return send(c, rsp)
+}
+
+func (s *Server) sendHistory(c *fiber.Ctx, kind, pairID string) error {
+	store := func() *history.Store {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return s.history
+	}()
+
+	if store == nil {
+		return fiber.ErrNotFound
+	}
+
+	at, err := history.ParseTime(c.Query("at"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := store.Closest(c.Params("exchangeID"), kind, pairID, at)
+	if err != nil {
+		if errors.Is(err, history.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rsp)
 }
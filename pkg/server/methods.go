@@ -3,27 +3,216 @@ package server
 import (
 	"context"
 	"exchanges/pkg/exchange"
+	"exchanges/pkg/history"
+	"exchanges/pkg/quality"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"net"
+	"sort"
+	"time"
 )
 
 func (s *Server) SetExchange(obj exchange.Exchange) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.exchanges[obj.GetID()] = obj
+	if row, ok := s.exchanges[obj.GetID()]; ok {
+		row.obj = obj
+	} else {
+		s.exchanges[obj.GetID()] = &entry{obj: obj}
+	}
+
+	s.cacheDB.Flush()
+}
+
+func (s *Server) RemoveExchange(exchangeID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.exchanges, exchangeID)
+
+	s.quality.RemoveExchange(exchangeID)
+	s.cacheDB.Flush()
+}
+
+func (s *Server) SetExchangeEnabled(exchangeID string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.exchanges[exchangeID]
+	if !ok {
+		return fmt.Errorf("unknown exchange: %s", exchangeID)
+	}
+
+	row.disabled = !enabled
+
+	s.cacheDB.Flush()
+
+	return nil
+}
+
+func (s *Server) SetWireLog(exchangeID string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.exchanges[exchangeID]
+	if !ok {
+		return fmt.Errorf("unknown exchange: %s", exchangeID)
+	}
+
+	obj, ok := row.obj.(exchange.WireLogger)
+	if !ok {
+		return fmt.Errorf("exchange %s has no wire log", exchangeID)
+	}
+
+	obj.SetWireLog(enabled)
+
+	return nil
+}
+
+func (s *Server) FlushCache(exchangeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.exchanges[exchangeID]
+	if !ok {
+		return fmt.Errorf("unknown exchange: %s", exchangeID)
+	}
+
+	if obj, ok := row.obj.(exchange.CacheFlusher); ok {
+		obj.FlushCache()
+	}
+
+	s.cacheDB.Flush()
+
+	return nil
+}
+
+func (s *Server) ExchangeStatuses() []ExchangeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []ExchangeStatus
+
+	for exchangeID, row := range s.exchanges {
+		result = append(result, ExchangeStatus{
+			Id:      exchangeID,
+			Enabled: !row.disabled,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+
+	return result
+}
+
+func (s *Server) SetReloader(fn func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reload = fn
+}
+
+func (s *Server) getExchange(exchangeID string) (exchange.Exchange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.exchanges[exchangeID]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, "exchange not found")
+	}
+
+	if row.disabled {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "exchange disabled")
+	}
+
+	return row.obj, nil
+}
+
+func (s *Server) SetHistory(store *history.Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = store
+}
+
+func (s *Server) SetQuality(cfg quality.Config) {
+	s.quality.SetConfig(cfg)
+	s.cacheDB.Flush()
+}
+
+func (s *Server) QualityReport() quality.Report {
+	return s.quality.Report()
+}
+
+func (s *Server) Validator() *quality.Validator {
+	return s.quality
 }
 
 func (s *Server) Run(ctx context.Context, addr string) error {
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
 
 	go func() {
-		defer close(errCh)
 		errCh <- s.engine.Listen(addr)
 	}()
 
+	if len(s.cfg.GRPCAddr) > 0 {
+		listener, err := net.Listen("tcp", s.cfg.GRPCAddr)
+		if err != nil {
+			_ = s.shutdown()
+			return err
+		}
+
+		go func() {
+			errCh <- s.grpc.Serve(listener)
+		}()
+	}
+
 	select {
 	case <-ctx.Done():
-		return s.engine.ShutdownWithTimeout(shutdownTimeout)
+		return s.shutdown()
 	case err := <-errCh:
+		_ = s.shutdown()
 		return err
 	}
 }
+
+func (s *Server) shutdown() error {
+	close(s.done)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		s.grpc.GracefulStop()
+	}()
+
+	err := s.engine.ShutdownWithTimeout(s.cfg.ShutdownTimeout)
+
+	select {
+	case <-stopped:
+	case <-time.After(s.cfg.ShutdownTimeout):
+		s.grpc.Stop()
+	}
+
+	return err
+}
+
+func (s *Server) enabledExchanges() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []string
+
+	for exchangeID, row := range s.exchanges {
+		if !row.disabled {
+			result = append(result, exchangeID)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
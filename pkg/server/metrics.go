@@ -0,0 +1,14 @@
+package server
+
+import (
+	"exchanges/pkg/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (s *Server) initMetrics(engine *fiber.App) {
+	engine.Get("/metrics", func(c *fiber.Ctx) error {
+		c.Set("Content-Type", metricsContentType)
+
+		return metrics.Default.Write(c.Response().BodyWriter())
+	})
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"exchanges/pkg/logger"
+	"exchanges/pkg/metrics"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+	"time"
+)
+
+func (s *Server) initMiddleware(engine *fiber.App) {
+	engine.Use(func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestID := c.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = logger.NewRequestID()
+		}
+
+		c.Set(requestIDHeader, requestID)
+		c.SetUserContext(logger.WithRequestID(c.UserContext(), requestID))
+
+		if err := c.Next(); err != nil {
+			if err = c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		d := time.Since(start)
+		status := c.Response().StatusCode()
+
+		metrics.ObserveHTTP(c.Route().Path, c.Method(), status, d)
+
+		slog.LogAttrs(c.UserContext(), slog.LevelInfo, "access",
+			slog.String("request_id", requestID),
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Duration("duration", d),
+			slog.String("ip", c.IP()),
+			slog.Int("bytes", len(c.Response().Body())),
+		)
+
+		return nil
+	})
+}
+
+func validRequestID(value string) bool {
+	if len(value) == 0 || len(value) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"exchanges/pkg/openapi"
+	"exchanges/pkg/render"
+	"reflect"
+	"regexp"
+)
+
+var (
+	pathParamRe = regexp.MustCompile(`:(\w+)`)
+)
+
+func (s *Server) openAPI(routes []route) *openapi.Document {
+	s.specOnce.Do(func() {
+		s.spec = newOpenAPI(routes)
+	})
+
+	return s.spec
+}
+
+func newOpenAPI(routes []route) *openapi.Document {
+	doc := openapi.NewDocument(apiTitle, apiVersion)
+	errorSchema := doc.Schema(reflect.TypeOf(ErrorResponse{}))
+
+	for _, row := range routes {
+		var params []openapi.Parameter
+
+		for _, match := range pathParamRe.FindAllStringSubmatch(row.path, -1) {
+			params = append(params, openapi.Parameter{
+				Name:     match[1],
+				In:       openapi.InPath,
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+
+		content := jsonContent(doc.Schema(row.response))
+
+		if row.formats {
+			params = append(params, queryParam("format", "Response format, overrides the Accept header"))
+
+			for _, format := range render.Formats[1:] {
+				content[render.ContentType(format)] = openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+			}
+		}
+
+		var body *openapi.RequestBody
+
+		if row.request != nil {
+			body = &openapi.RequestBody{
+				Required: true,
+				Content:  jsonContent(doc.Schema(row.request)),
+			}
+		}
+
+		doc.AddOperation(row.method, apiPrefix+openAPIPath(row.path), &openapi.Operation{
+			OperationID: row.operationID,
+			Summary:     row.summary,
+			Parameters:  append(params, row.query...),
+			RequestBody: body,
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "OK",
+					Content:     content,
+				},
+				"default": {
+					Description: "Error",
+					Content:     jsonContent(errorSchema),
+				},
+			},
+		})
+	}
+
+	return doc
+}
+
+func openAPIPath(path string) string {
+	return pathParamRe.ReplaceAllString(path, "{$1}")
+}
+
+func queryParam(name, description string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          openapi.InQuery,
+		Description: description,
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+}
+
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{
+		"application/json": {Schema: schema},
+	}
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/history"
+	"exchanges/pkg/openapi"
+	"github.com/shopspring/decimal"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeExchange struct{}
+
+func (fakeExchange) GetID() string {
+	return "fake"
+}
+
+func (fakeExchange) GetPairs(context.Context) ([]exchange.Pair, error) {
+	return []exchange.Pair{
+		{Id: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: decimal.RequireFromString("26751.2"), Bid: decimal.RequireFromString("26751.1")},
+		{Id: "ETH-USDT", BaseAsset: "ETH", QuoteAsset: "USDT", Ask: decimal.RequireFromString("1630.52"), Bid: decimal.RequireFromString("1630.51"), Stale: true},
+	}, nil
+}
+
+func (fakeExchange) GetOrderBook(_ context.Context, pairID string) (exchange.OrderBook, error) {
+	if pairID != "BTC-USDT" {
+		return exchange.OrderBook{}, exchange.NewError(exchange.ErrPairNotFound, "fake", "404", "pair not found")
+	}
+
+	return exchange.OrderBook{
+		Ask:      [][]decimal.Decimal{{decimal.RequireFromString("26751.2"), decimal.RequireFromString("0.5")}},
+		Bid:      [][]decimal.Decimal{{decimal.RequireFromString("26751.1"), decimal.RequireFromString("0.7")}},
+		Sequence: 7,
+		UpdateID: 8,
+	}, nil
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	store, err := history.NewStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	obj := fakeExchange{}
+	pairs, _ := obj.GetPairs(context.Background())
+	book, _ := obj.GetOrderBook(context.Background(), "BTC-USDT")
+
+	if err = store.Write(obj.GetID(), history.KindPairs, "", pairs); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.Write(obj.GetID(), history.KindOrderBook, "BTC-USDT", book); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(Config{})
+	srv.SetExchange(obj)
+	srv.SetHistory(store)
+
+	return srv
+}
+
+func get(t *testing.T, srv *Server, path string) (int, string, []byte) {
+	t.Helper()
+
+	rsp, err := srv.engine.Test(httptest.NewRequest("GET", path, nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rsp.StatusCode, rsp.Header.Get("Deprecation"), body
+}
+
+func loadSpec(t *testing.T, srv *Server) *openapi.Document {
+	t.Helper()
+
+	status, _, body := get(t, srv, apiPrefix+"/openapi.json")
+	if status != 200 {
+		t.Fatalf("openapi.json status = %d", status)
+	}
+
+	doc := new(openapi.Document)
+
+	if err := json.Unmarshal(body, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenAPI != openapi.Version {
+		t.Fatalf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+
+	return doc
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	srv := newTestServer(t)
+	doc := loadSpec(t, srv)
+
+	served := make(map[string]bool)
+
+	for _, row := range srv.engine.GetRoutes(true) {
+		if row.Method != "GET" && row.Method != "POST" || !strings.HasPrefix(row.Path, apiPrefix+"/") || row.Path == apiPrefix+"/openapi.json" {
+			continue
+		}
+
+		path := openAPIPath(row.Path)
+		served[path] = true
+
+		if item, ok := doc.Paths[path]; !ok || item[strings.ToLower(row.Method)] == nil {
+			t.Errorf("route %s %s is missing in the spec", row.Method, row.Path)
+		}
+	}
+
+	for path := range doc.Paths {
+		if !served[path] {
+			t.Errorf("spec path %s has no handler", path)
+		}
+	}
+}
+
+func TestOpenAPIResponses(t *testing.T) {
+	srv := newTestServer(t)
+	doc := loadSpec(t, srv)
+
+	tests := []struct {
+		name   string
+		spec   string
+		path   string
+		status int
+		// legacy is the response type of the deprecated route when it differs from the spec.
+		legacy reflect.Type
+	}{
+		{name: "exchanges", spec: "/exchanges", path: "/exchanges", status: 200},
+		{name: "pairs", spec: "/{exchangeID}/pairs", path: "/fake/pairs", status: 200, legacy: reflect.TypeOf([]exchange.Pair{})},
+		{name: "order_book", spec: "/{exchangeID}/orderbook/{pairID}", path: "/fake/orderbook/BTC-USDT", status: 200},
+		{name: "order_book_not_found", spec: "/{exchangeID}/orderbook/{pairID}", path: "/fake/orderbook/NONE", status: 404},
+		{name: "exchange_not_found", spec: "/{exchangeID}/pairs", path: "/none/pairs", status: 404},
+		{name: "history_pairs", spec: "/history/{exchangeID}/pairs", path: "/history/fake/pairs", status: 200},
+		{name: "history_order_book", spec: "/history/{exchangeID}/orderbook/{pairID}", path: "/history/fake/orderbook/BTC-USDT", status: 200},
+		{name: "history_bad_time", spec: "/history/{exchangeID}/pairs", path: "/history/fake/pairs?at=yesterday", status: 400},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			item, ok := doc.Paths[apiPrefix+tt.spec]
+			if !ok || item["get"] == nil {
+				t.Fatalf("spec has no GET %s", apiPrefix+tt.spec)
+			}
+
+			response, ok := item["get"].Responses["200"]
+			if tt.status != 200 {
+				response, ok = item["get"].Responses["default"]
+			}
+
+			if !ok {
+				t.Fatalf("spec has no response for status %d", tt.status)
+			}
+
+			for _, path := range []string{apiPrefix + tt.path, tt.path} {
+				status, deprecation, body := get(t, srv, path)
+
+				if status != tt.status {
+					t.Fatalf("GET %s status = %d, want %d: %s", path, status, tt.status, body)
+				}
+
+				if wantDeprecated := !strings.HasPrefix(path, apiPrefix); (deprecation == "true") != wantDeprecated {
+					t.Errorf("GET %s Deprecation = %q", path, deprecation)
+				}
+
+				schema := response.Content["application/json"].Schema
+				if tt.legacy != nil && !strings.HasPrefix(path, apiPrefix) {
+					schema = doc.Schema(tt.legacy)
+				}
+
+				if err := doc.Validate(schema, body); err != nil {
+					t.Errorf("GET %s does not match the spec: %v\n%s", path, err, body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PairsResponse struct {
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Pairs      []exchange.Pair `json:"pairs"`
+}
+
+type pairsQuery struct {
+	filter exchange.PairFilter
+	limit  int
+	after  *pairsCursor
+}
+
+// pairsCursor marks the last pair of a page, along with the filter and sort it was taken from.
+type pairsCursor struct {
+	Base   string          `json:"b,omitempty"`
+	Quote  string          `json:"q,omitempty"`
+	Prefix string          `json:"p,omitempty"`
+	Search string          `json:"s,omitempty"`
+	SortBy string          `json:"o"`
+	Desc   bool            `json:"d,omitempty"`
+	Key    decimal.Decimal `json:"k"`
+	ID     string          `json:"i"`
+}
+
+func parsePairsQuery(c *fiber.Ctx) (pairsQuery, error) {
+	q := pairsQuery{
+		filter: exchange.PairFilter{
+			Base:   c.Query("base"),
+			Quote:  c.Query("quote"),
+			Prefix: c.Query("prefix"),
+			Search: c.Query("q"),
+			SortBy: c.Query("sort", exchange.SortByID),
+		},
+	}
+
+	if !exchange.ValidSortKey(q.filter.SortBy) {
+		return q, fiber.NewError(fiber.StatusBadRequest, "sort must be one of "+strings.Join(exchange.SortKeys, ", "))
+	}
+
+	switch c.Query("order", "asc") {
+	case "asc":
+	case "desc":
+		q.filter.Desc = true
+	default:
+		return q, fiber.NewError(fiber.StatusBadRequest, "order must be asc or desc")
+	}
+
+	if value := c.Query("limit"); len(value) > 0 {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxPairsLimit {
+			return q, fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxPairsLimit))
+		}
+
+		q.limit = limit
+	}
+
+	if value := c.Query("cursor"); len(value) > 0 {
+		after, err := decodeCursor(value)
+		if err != nil {
+			return q, fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+
+		if after.filter() != q.filter {
+			return q, fiber.NewError(fiber.StatusBadRequest, "cursor does not match the filter and sort of the query")
+		}
+
+		q.after = &after
+	}
+
+	return q, nil
+}
+
+func (q pairsQuery) apply(pairs []exchange.Pair) PairsResponse {
+	result := q.filter.Apply(pairs)
+	rsp := PairsResponse{Total: len(result), Pairs: result}
+
+	if q.after != nil {
+		rsp.Pairs = rsp.Pairs[sort.Search(len(rsp.Pairs), func(i int) bool {
+			return q.filter.Compare(rsp.Pairs[i], q.after.Key, q.after.ID) > 0
+		}):]
+	}
+
+	if q.limit == 0 || q.limit >= len(rsp.Pairs) {
+		return rsp
+	}
+
+	rsp.Pairs = rsp.Pairs[:q.limit]
+	last := rsp.Pairs[q.limit-1]
+
+	rsp.NextCursor = encodeCursor(pairsCursor{
+		Base:   q.filter.Base,
+		Quote:  q.filter.Quote,
+		Prefix: q.filter.Prefix,
+		Search: q.filter.Search,
+		SortBy: q.filter.SortBy,
+		Desc:   q.filter.Desc,
+		Key:    q.filter.SortKey(last),
+		ID:     last.Id,
+	})
+
+	return rsp
+}
+
+func (p pairsCursor) filter() exchange.PairFilter {
+	return exchange.PairFilter{
+		Base:   p.Base,
+		Quote:  p.Quote,
+		Prefix: p.Prefix,
+		Search: p.Search,
+		SortBy: p.SortBy,
+		Desc:   p.Desc,
+	}
+}
+
+func encodeCursor(cursor pairsCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value string) (pairsCursor, error) {
+	var cursor pairsCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+
+	if err = json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, err
+	}
+
+	if len(cursor.ID) == 0 {
+		return cursor, strconv.ErrSyntax
+	}
+
+	return cursor, nil
+}
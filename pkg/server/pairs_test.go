@@ -0,0 +1,246 @@
+package server
+
+import (
+	"encoding/json"
+	"exchanges/pkg/exchange"
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testPairs = []exchange.Pair{
+	{Id: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", Ask: decimal.RequireFromString("101"), Bid: decimal.RequireFromString("99"), Volume: decimal.NewFromInt(5)},
+	{Id: "ETH-USDT", BaseAsset: "ETH", QuoteAsset: "USDT", Ask: decimal.RequireFromString("10.1"), Bid: decimal.RequireFromString("10"), Volume: decimal.NewFromInt(50)},
+	{Id: "ETH-BTC", BaseAsset: "ETH", QuoteAsset: "BTC", Ask: decimal.RequireFromString("0.06"), Bid: decimal.RequireFromString("0.05"), Volume: decimal.NewFromInt(5)},
+	{Id: "SOL-USDT", BaseAsset: "SOL", QuoteAsset: "USDT", Ask: decimal.RequireFromString("20.2"), Bid: decimal.RequireFromString("19.8"), Volume: decimal.NewFromInt(1)},
+	{Id: "XRP-USDT", BaseAsset: "XRP", QuoteAsset: "USDT", Ask: decimal.RequireFromString("0.5"), Bid: decimal.RequireFromString("0.5"), Volume: decimal.NewFromInt(500)},
+}
+
+func parseQuery(t *testing.T, query string) (pairsQuery, int) {
+	t.Helper()
+
+	var q pairsQuery
+
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler})
+	app.Get("/", func(c *fiber.Ctx) (err error) {
+		q, err = parsePairsQuery(c)
+		if err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	rsp, err := app.Test(httptest.NewRequest("GET", "/?"+query, nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = rsp.Body.Close()
+
+	return q, rsp.StatusCode
+}
+
+func pairIDs(pairs []exchange.Pair) []string {
+	result := make([]string, 0, len(pairs))
+
+	for _, row := range pairs {
+		result = append(result, row.Id)
+	}
+
+	return result
+}
+
+func TestParsePairsQuery(t *testing.T) {
+	cursor := encodeCursor(pairsCursor{SortBy: exchange.SortByVolume, Desc: true, Quote: "USDT", Key: decimal.NewFromInt(5), ID: "BTC-USDT"})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   pairsQuery
+	}{
+		{name: "default", query: "", status: 200, want: pairsQuery{filter: exchange.PairFilter{SortBy: exchange.SortByID}}},
+		{name: "filters", query: "base=btc&quote=usdt&prefix=B&q=T&sort=spread&order=desc&limit=10", status: 200, want: pairsQuery{
+			filter: exchange.PairFilter{Base: "btc", Quote: "usdt", Prefix: "B", Search: "T", SortBy: exchange.SortBySpread, Desc: true},
+			limit:  10,
+		}},
+		{name: "cursor", query: "quote=USDT&sort=volume&order=desc&cursor=" + cursor, status: 200, want: pairsQuery{
+			filter: exchange.PairFilter{Quote: "USDT", SortBy: exchange.SortByVolume, Desc: true},
+			after:  &pairsCursor{SortBy: exchange.SortByVolume, Desc: true, Quote: "USDT", Key: decimal.NewFromInt(5), ID: "BTC-USDT"},
+		}},
+		{name: "bad_sort", query: "sort=name", status: 400},
+		{name: "bad_order", query: "order=up", status: 400},
+		{name: "zero_limit", query: "limit=0", status: 400},
+		{name: "big_limit", query: "limit=1001", status: 400},
+		{name: "bad_limit", query: "limit=ten", status: 400},
+		{name: "bad_cursor", query: "cursor=%21%21", status: 400},
+		{name: "offset_cursor", query: "cursor=MTA", status: 400},
+		{name: "cursor_other_sort", query: "quote=USDT&sort=volume&cursor=" + cursor, status: 400},
+		{name: "cursor_other_filter", query: "sort=volume&order=desc&cursor=" + cursor, status: 400},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, status := parseQuery(t, tt.query)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+
+			if status != 200 {
+				return
+			}
+
+			if got.filter != tt.want.filter || got.limit != tt.want.limit {
+				t.Errorf("query = %+v, want %+v", got, tt.want)
+			}
+
+			if (got.after == nil) != (tt.want.after == nil) || got.after != nil && (got.after.ID != tt.want.after.ID || !got.after.Key.Equal(tt.want.after.Key)) {
+				t.Errorf("after = %+v, want %+v", got.after, tt.want.after)
+			}
+		})
+	}
+}
+
+func TestPairsQueryApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter exchange.PairFilter
+		want   []string
+	}{
+		{name: "id", filter: exchange.PairFilter{SortBy: exchange.SortByID}, want: []string{"BTC-USDT", "ETH-BTC", "ETH-USDT", "SOL-USDT", "XRP-USDT"}},
+		{name: "id_desc", filter: exchange.PairFilter{SortBy: exchange.SortByID, Desc: true}, want: []string{"XRP-USDT", "SOL-USDT", "ETH-USDT", "ETH-BTC", "BTC-USDT"}},
+		{name: "volume", filter: exchange.PairFilter{SortBy: exchange.SortByVolume}, want: []string{"SOL-USDT", "BTC-USDT", "ETH-BTC", "ETH-USDT", "XRP-USDT"}},
+		{name: "volume_desc", filter: exchange.PairFilter{SortBy: exchange.SortByVolume, Desc: true}, want: []string{"XRP-USDT", "ETH-USDT", "ETH-BTC", "BTC-USDT", "SOL-USDT"}},
+		{name: "price", filter: exchange.PairFilter{SortBy: exchange.SortByPrice}, want: []string{"ETH-BTC", "XRP-USDT", "ETH-USDT", "SOL-USDT", "BTC-USDT"}},
+		{name: "spread", filter: exchange.PairFilter{SortBy: exchange.SortBySpread}, want: []string{"XRP-USDT", "ETH-USDT", "BTC-USDT", "SOL-USDT", "ETH-BTC"}},
+		{name: "base", filter: exchange.PairFilter{SortBy: exchange.SortByID, Base: "eth"}, want: []string{"ETH-BTC", "ETH-USDT"}},
+		{name: "quote", filter: exchange.PairFilter{SortBy: exchange.SortByID, Quote: "btc"}, want: []string{"ETH-BTC"}},
+		{name: "prefix", filter: exchange.PairFilter{SortBy: exchange.SortByID, Prefix: "s"}, want: []string{"SOL-USDT"}},
+		{name: "search", filter: exchange.PairFilter{SortBy: exchange.SortByID, Search: "btc"}, want: []string{"BTC-USDT", "ETH-BTC"}},
+		{name: "none", filter: exchange.PairFilter{SortBy: exchange.SortByID, Base: "DOGE"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			rsp := pairsQuery{filter: tt.filter}.apply(testPairs)
+
+			if got := pairIDs(rsp.Pairs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pairs = %v, want %v", got, tt.want)
+			}
+
+			if rsp.Total != len(tt.want) || len(rsp.NextCursor) > 0 {
+				t.Errorf("total = %d, next = %q", rsp.Total, rsp.NextCursor)
+			}
+
+			for limit := 1; limit <= len(tt.want); limit++ {
+				q := pairsQuery{filter: tt.filter, limit: limit}
+
+				var got []string
+
+				for pages := 0; ; pages++ {
+					if pages > len(tt.want) {
+						t.Fatalf("limit %d: too many pages", limit)
+					}
+
+					page := q.apply(testPairs)
+					if page.Total != len(tt.want) {
+						t.Fatalf("limit %d: total = %d", limit, page.Total)
+					}
+
+					got = append(got, pairIDs(page.Pairs)...)
+
+					if len(page.NextCursor) == 0 {
+						break
+					}
+
+					after, err := decodeCursor(page.NextCursor)
+					if err != nil || after.filter() != tt.filter {
+						t.Fatalf("limit %d: cursor %+v: %v", limit, after, err)
+					}
+
+					q.after = &after
+				}
+
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("limit %d: pages = %v, want %v", limit, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPairsCursorKeepsPosition(t *testing.T) {
+	filter := exchange.PairFilter{SortBy: exchange.SortByID}
+
+	first := pairsQuery{filter: filter, limit: 2}.apply(testPairs)
+	if got := pairIDs(first.Pairs); !reflect.DeepEqual(got, []string{"BTC-USDT", "ETH-BTC"}) {
+		t.Fatalf("first page = %v", got)
+	}
+
+	after, err := decodeCursor(first.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := pairsQuery{filter: filter, limit: 2, after: &after}.apply(testPairs[1:])
+
+	if got := pairIDs(second.Pairs); !reflect.DeepEqual(got, []string{"ETH-USDT", "SOL-USDT"}) {
+		t.Errorf("second page after a listed pair was removed = %v", got)
+	}
+}
+
+func TestPairsResponse(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, _, body := get(t, srv, apiPrefix+"/fake/pairs?limit=1")
+	if status != 200 {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+
+	var page PairsResponse
+
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Total != 2 || len(page.NextCursor) == 0 || !reflect.DeepEqual(pairIDs(page.Pairs), []string{"BTC-USDT"}) {
+		t.Fatalf("first page = %s", body)
+	}
+
+	status, _, body = get(t, srv, apiPrefix+"/fake/pairs?limit=1&cursor="+url.QueryEscape(page.NextCursor))
+	if status != 200 {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+
+	page = PairsResponse{}
+
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Total != 2 || len(page.NextCursor) != 0 || !reflect.DeepEqual(pairIDs(page.Pairs), []string{"ETH-USDT"}) {
+		t.Errorf("last page = %s", body)
+	}
+
+	status, _, body = get(t, srv, apiPrefix+"/fake/pairs?limit=1&format=csv")
+	if status != 200 || !strings.HasPrefix(string(body), "id,base_asset") || strings.Count(string(body), "\n") != 2 {
+		t.Errorf("csv page = %d %s", status, body)
+	}
+
+	status, _, body = get(t, srv, "/fake/pairs?limit=1")
+
+	var pairs []exchange.Pair
+
+	if err := json.Unmarshal(body, &pairs); status != 200 || err != nil || !reflect.DeepEqual(pairIDs(pairs), []string{"BTC-USDT"}) {
+		t.Errorf("legacy page = %d %s: %v", status, body, err)
+	}
+}
@@ -1,22 +1,81 @@
 package server
 
 import (
+	"exchanges/pkg/cache"
 	"exchanges/pkg/exchange"
+	"exchanges/pkg/history"
+	"exchanges/pkg/openapi"
+	"exchanges/pkg/quality"
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
 	"sync"
+	"time"
 )
 
-func NewServer() *Server {
+func NewServer(cfg Config) *Server {
+	if cfg.ReqTimeout <= 0 {
+		cfg.ReqTimeout = reqTimeout
+	}
+
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = shutdownTimeout
+	}
+
 	obj := new(Server)
+	obj.cfg = cfg
 	obj.mu = new(sync.Mutex)
-	obj.exchanges = make(map[string]exchange.Exchange)
+	obj.exchanges = make(map[string]*entry)
+	obj.cacheDB = cache.NewLimitedDB("server", responseCacheSize)
+	obj.quality = quality.NewValidator(quality.Config{})
+	obj.specOnce = new(sync.Once)
+	obj.done = make(chan struct{})
 	obj.init()
+	obj.initGRPC()
 
 	return obj
 }
 
+type Config struct {
+	ReqTimeout      time.Duration
+	ShutdownTimeout time.Duration
+	ReadyRequireAll bool
+	GRPCAddr        string
+}
+
 type Server struct {
+	cfg       Config
 	mu        *sync.Mutex
 	engine    *fiber.App
-	exchanges map[string]exchange.Exchange
+	exchanges map[string]*entry
+	cacheDB   *cache.DB
+	history   *history.Store
+	quality   *quality.Validator
+	reload    func() error
+	clients   map[string]*client
+	grpc      *grpc.Server
+	done      chan struct{}
+	specOnce  *sync.Once
+	spec      *openapi.Document
+}
+
+type entry struct {
+	obj      exchange.Exchange
+	disabled bool
+}
+
+type ExchangeStatus struct {
+	Id      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+type KeyUsage struct {
+	Name     string `json:"name"`
+	Requests uint64 `json:"requests"`
+	Limited  uint64 `json:"limited"`
+	Denied   uint64 `json:"denied"`
+}
+
+type Readiness struct {
+	Ready     bool                       `json:"ready"`
+	Exchanges map[string]exchange.Health `json:"exchanges"`
 }
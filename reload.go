@@ -0,0 +1,127 @@
+package main
+
+import (
+	"exchanges/pkg/config"
+	"exchanges/pkg/exchange"
+	"exchanges/pkg/history"
+	"exchanges/pkg/server"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+func newReloader(load func() (*config.Config, error), srv *server.Server) *reloader {
+	return &reloader{
+		mu:      new(sync.Mutex),
+		load:    load,
+		srv:     srv,
+		current: make(map[string]config.Exchange),
+		objs:    make(map[string]exchange.Exchange),
+	}
+}
+
+type reloader struct {
+	mu       *sync.Mutex
+	load     func() (*config.Config, error)
+	srv      *server.Server
+	recorder *history.Recorder
+	cfg      *config.Config
+	current  map[string]config.Exchange
+	objs     map[string]exchange.Exchange
+}
+
+func (r *reloader) setRecorder(recorder *history.Recorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recorder = recorder
+
+	for _, obj := range r.objs {
+		recorder.SetExchange(obj)
+	}
+
+	if r.cfg != nil {
+		recorder.SetOrderBooks(r.cfg.HistoryBooks())
+	}
+}
+
+func (r *reloader) reload() error {
+	cfg, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	return r.apply(cfg)
+}
+
+func (r *reloader) apply(cfg *config.Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := make(map[string]config.Exchange)
+	built := make(map[string]exchange.Exchange)
+
+	for _, exchangeID := range cfg.EnabledExchanges() {
+		value := cfg.Exchanges[exchangeID]
+		next[exchangeID] = value
+
+		if prev, ok := r.current[exchangeID]; ok && reflect.DeepEqual(prev, value) {
+			continue
+		}
+
+		obj, err := value.New(exchangeID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", exchangeID, err)
+		}
+
+		built[exchangeID] = obj
+	}
+
+	if err := r.srv.SetAPIKeys(cfg.Auth.APIKeys()); err != nil {
+		return err
+	}
+
+	r.srv.SetQuality(cfg.Quality.Config())
+
+	if r.cfg != nil {
+		if !reflect.DeepEqual(r.cfg.Server, cfg.Server) || r.cfg.History.Dir != cfg.History.Dir || r.cfg.History.Retention != cfg.History.Retention {
+			slog.Warn("config reload: server, history.dir and history.retention changes apply after restart")
+		}
+	}
+
+	for exchangeID := range r.current {
+		if _, ok := next[exchangeID]; !ok {
+			r.srv.RemoveExchange(exchangeID)
+			delete(r.objs, exchangeID)
+
+			if r.recorder != nil {
+				r.recorder.RemoveExchange(exchangeID)
+			}
+
+			slog.Info("exchange removed", "exchange", exchangeID)
+		}
+	}
+
+	for exchangeID, obj := range built {
+		r.srv.SetExchange(obj)
+		r.objs[exchangeID] = obj
+
+		if r.recorder != nil {
+			r.recorder.SetExchange(obj)
+		}
+
+		slog.Info("exchange configured", "exchange", exchangeID)
+	}
+
+	if r.recorder != nil {
+		r.recorder.SetOrderBooks(cfg.HistoryBooks())
+		r.recorder.SetInterval(time.Duration(cfg.History.Interval))
+	}
+
+	r.cfg = cfg
+	r.current = next
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"exchanges/pkg/config"
+	"exchanges/pkg/history"
+	"exchanges/pkg/logger"
+	"exchanges/pkg/server"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	configFile string
+	logFile    string
+	addr       string
+)
+
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&configFile, "config", "", "Path to JSON config file")
+	fs.StringVar(&logFile, "logFile", "", "Path to log file, overrides server.log_file")
+	fs.StringVar(&addr, "addr", "", "server addres, overrides server.addr")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	appLog, closer, err := logger.New(cfg.Server.Logger())
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = closer.Close()
+	}()
+
+	slog.SetDefault(appLog)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill, syscall.SIGTERM)
+	defer stop()
+
+	srv := server.NewServer(server.Config{
+		ReqTimeout:      time.Duration(cfg.Server.ReqTimeout),
+		ShutdownTimeout: time.Duration(cfg.Server.ShutdownTimeout),
+		ReadyRequireAll: cfg.Server.ReadyRequireAll,
+		GRPCAddr:        cfg.Server.GRPCAddr,
+	})
+
+	reload := newReloader(loadConfig, srv)
+
+	if err = reload.apply(cfg); err != nil {
+		return err
+	}
+
+	srv.SetReloader(reload.reload)
+
+	if len(cfg.History.Dir) > 0 {
+		store, err := history.NewStore(cfg.History.Dir, time.Duration(cfg.History.Retention))
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = store.Close()
+		}()
+
+		recorder := history.NewRecorder(store, time.Duration(cfg.History.Interval))
+		recorder.SetValidator(srv.Validator())
+
+		reload.setRecorder(recorder)
+		srv.SetHistory(store)
+
+		go store.Run(ctx)
+		go recorder.Run(ctx)
+	}
+
+	go func() {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := reload.reload(); err != nil {
+					slog.Error("config reload failed", "error", err)
+				} else {
+					slog.Info("config reloaded")
+				}
+			}
+		}
+	}()
+
+	slog.Info("application start", "addr", cfg.Server.Addr)
+
+	if err := srv.Run(ctx, cfg.Server.Addr); err != nil {
+		return err
+	}
+
+	slog.Info("application stop")
+
+	return nil
+}
+
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(logFile) > 0 {
+		cfg.Server.LogFile = logFile
+	}
+
+	if len(addr) > 0 {
+		cfg.Server.Addr = addr
+	}
+
+	return cfg, nil
+}